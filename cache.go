@@ -0,0 +1,172 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"container/list"
+)
+
+// trCache is the LRU cache of the loaded translators of a _Locale,
+// keyed by (domain, lang). A key has a translator for every layer of
+// the FileSystem (see LayeredFS), the missing catalogs are cached too,
+// as an empty trChain.
+//
+// The max size limits the translators, and the missing catalogs
+// separately: the languages without a catalog (such as the "de_DE.UTF-8"
+// variants, see fallbackLanguages) don't evict the loaded ones. The
+// missing catalogs are limited even if the cache is unlimited (see
+// defaultMaxMisses), the languages of the requests are untrusted. The
+// pinned keys (the current view's) are never evicted.
+//
+// It's not safe for concurrent use, the _Locale's mutex guards it.
+type trCache struct {
+	maxSize  int        // the max number of translators and of missing catalogs, 0 is unlimited
+	lru      *list.List // *trCacheEntry, the most recently used first
+	entries  map[trCacheKey]*list.Element
+	pinned   map[trCacheKey]bool
+	size     int                              // the number of translators
+	misses   int                              // the number of missing catalogs
+	onRemove func(key trCacheKey, tr trChain) // called with the evicted and removed keys, or nil
+}
+
+// defaultMaxMisses is the max number of the missing catalogs
+// of an unlimited cache.
+const defaultMaxMisses = 1024
+
+type trCacheKey struct {
+	domain string
+	lang   string
+}
+
+type trCacheEntry struct {
+	key trCacheKey
+	tr  trChain
+}
+
+func newTrCache() *trCache {
+	return &trCache{
+		lru:     list.New(),
+		entries: make(map[trCacheKey]*list.Element),
+	}
+}
+
+// Get returns the translator of the domain and lang,
+// and marks it as the most recently used one.
+func (c *trCache) Get(domain, lang string) (tr trChain, ok bool) {
+	if e, ok := c.entries[trCacheKey{domain, lang}]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*trCacheEntry).tr, true
+	}
+	return nil, false
+}
+
+// Peek is like Get, but doesn't change the LRU order.
+func (c *trCache) Peek(domain, lang string) (tr trChain, ok bool) {
+	if e, ok := c.entries[trCacheKey{domain, lang}]; ok {
+		return e.Value.(*trCacheEntry).tr, true
+	}
+	return nil, false
+}
+
+// Add adds the translator of the domain and lang,
+// the least recently used ones are evicted if the cache is full.
+func (c *trCache) Add(domain, lang string, tr trChain) {
+	key := trCacheKey{domain, lang}
+	if e, ok := c.entries[key]; ok {
+		c.count(e.Value.(*trCacheEntry).tr, -1)
+		e.Value.(*trCacheEntry).tr = tr
+		c.count(tr, +1)
+		c.lru.MoveToFront(e)
+	} else {
+		c.entries[key] = c.lru.PushFront(&trCacheEntry{key: key, tr: tr})
+		c.count(tr, +1)
+	}
+	c.evict()
+}
+
+// Remove removes the translators of the domain and lang,
+// an empty domain or lang matches all.
+func (c *trCache) Remove(domain, lang string) {
+	for key, e := range c.entries {
+		if (domain == "" || domain == key.domain) && (lang == "" || lang == key.lang) {
+			c.remove(e)
+		}
+	}
+}
+
+// Pin sets the keys of the domain and langs which are never evicted,
+// the keys pinned before are unpinned.
+func (c *trCache) Pin(domain string, langs []string) {
+	c.pinned = make(map[trCacheKey]bool, len(langs))
+	for _, lang := range langs {
+		c.pinned[trCacheKey{domain, lang}] = true
+	}
+	c.evict()
+}
+
+// SetMaxSize sets the max number of translators, 0 is unlimited.
+func (c *trCache) SetMaxSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	c.maxSize = n
+	c.evict()
+}
+
+// Len returns the number of cached keys.
+func (c *trCache) Len() int {
+	return c.lru.Len()
+}
+
+// Size returns the number of cached translators.
+func (c *trCache) Size() int {
+	return c.size
+}
+
+func (c *trCache) count(tr trChain, delta int) {
+	if len(tr) == 0 {
+		c.misses += delta
+	} else {
+		c.size += delta * len(tr)
+	}
+}
+
+func (c *trCache) remove(e *list.Element) {
+	entry := e.Value.(*trCacheEntry)
+	c.lru.Remove(e)
+	delete(c.entries, entry.key)
+	c.count(entry.tr, -1)
+	if c.onRemove != nil {
+		c.onRemove(entry.key, entry.tr)
+	}
+}
+
+// maxMisses returns the max number of the missing catalogs.
+func (c *trCache) maxMisses() int {
+	if c.maxSize > 0 {
+		return c.maxSize
+	}
+	return defaultMaxMisses
+}
+
+// evict removes the least recently used translators (or missing
+// catalogs) which aren't pinned, until they fit the max size.
+func (c *trCache) evict() {
+	var (
+		maxMisses = c.maxMisses()
+		full      = c.maxSize > 0 && c.size > c.maxSize
+	)
+	for e := c.lru.Back(); e != nil && (full || c.misses > maxMisses); {
+		prev := e.Prev()
+		entry := e.Value.(*trCacheEntry)
+		if !c.pinned[entry.key] {
+			if len(entry.tr) == 0 && c.misses > maxMisses || len(entry.tr) != 0 && full {
+				c.remove(e)
+			}
+		}
+		full = c.maxSize > 0 && c.size > c.maxSize
+		e = prev
+	}
+}
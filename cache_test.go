@@ -0,0 +1,119 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTrCache(t *testing.T) {
+	var removed int
+	c := newTrCache()
+	c.onRemove = func(key trCacheKey, tr trChain) { removed++ }
+	a, b := trChain{&translator{}}, trChain{&translator{}}
+	c.Add("hello", "zh_CN", a)
+	c.Add("hello", "zh_TW", b)
+	c.Add("other", "zh_CN", nil)
+	tAssert(t, c.Len() == 3 && c.Size() == 2, c.Len(), c.Size())
+
+	tr, ok := c.Get("hello", "zh_CN")
+	tAssert(t, ok && tr[0] == a[0])
+
+	// "hello/zh_TW" is the least recently used translator,
+	// the missing catalog isn't counted
+	c.SetMaxSize(1)
+	tAssert(t, c.Len() == 2 && c.Size() == 1, c.Len(), c.Size())
+	_, ok = c.Peek("hello", "zh_TW")
+	tAssert(t, !ok)
+	_, ok = c.Peek("other", "zh_CN")
+	tAssert(t, ok)
+
+	c.Add("hello", "zh_TW", b)
+	_, ok = c.Peek("hello", "zh_CN")
+	tAssert(t, !ok)
+
+	// at most 1 missing catalog
+	c.Add("other", "zh_TW", nil)
+	_, ok = c.Peek("other", "zh_CN")
+	tAssert(t, !ok && c.Len() == 2, c.Len())
+
+	// the pinned keys are kept
+	c.Pin("hello", []string{"zh_TW", "zh"})
+	c.Add("hello", "zh_CN", a)
+	_, ok = c.Peek("hello", "zh_TW")
+	tAssert(t, ok && c.Size() == 1, c.Size())
+	c.Pin("hello", nil)
+	c.Add("hello", "zh_CN", a)
+	_, ok = c.Peek("hello", "zh_TW")
+	tAssert(t, !ok && c.Size() == 1, c.Size())
+
+	c.SetMaxSize(0)
+	c.Add("other", "zh_CN", nil)
+	c.Remove("", "zh_CN")
+	tAssert(t, c.Len() == 1, c.Len())
+	c.Remove("other", "")
+	tAssert(t, c.Len() == 0 && c.Size() == 0, c.Len(), c.Size())
+	tAssert(t, removed == 8, removed)
+
+	// the missing catalogs of an unlimited cache
+	for i := 0; i < defaultMaxMisses+10; i++ {
+		c.Add("hello", fmt.Sprintf("xx_%d", i), nil)
+	}
+	c.Add("hello", "zh_CN", a)
+	tAssert(t, c.Len() == defaultMaxMisses+1 && c.Size() == 1, c.Len(), c.Size())
+}
+
+type countingFS struct {
+	FileSystem
+	loads map[string]int
+}
+
+func (p *countingFS) LoadMessagesFile(domain, lang, ext string) ([]byte, error) {
+	p.loads[lang]++
+	return p.FileSystem.LoadMessagesFile(domain, lang, ext)
+}
+
+func TestLocale_cache(t *testing.T) {
+	fs := &countingFS{OS("./examples/locale"), make(map[string]int)}
+	l := New("hello", "", fs).SetLanguage("zh_CN")
+	for i := 0; i < 10; i++ {
+		l.SetLanguage("zh_TW")
+		l.SetLanguage("zh_CN")
+	}
+	testLocal_zh_CN(t, l)
+	tAssert(t, fs.loads["zh_CN"] == 1 && fs.loads["zh_TW"] == 1, fs.loads)
+
+	l.Invalidate("hello", "zh_CN")
+	testLocal_zh_CN(t, l)
+	tAssert(t, fs.loads["zh_CN"] == 2 && fs.loads["zh_TW"] == 1, fs.loads)
+
+	// zh_CN, zh_TW and default, the missing catalogs (zh, ...) aren't counted
+	l.SetCacheSize(3)
+	for i := 0; i < 10; i++ {
+		l.SetLanguage("zh_TW")
+		l.SetLanguage("zh_CN")
+	}
+	tAssert(t, fs.loads["zh_CN"] == 2 && fs.loads["zh_TW"] == 1, fs.loads)
+
+	// the current catalogs (zh_CN and default) are kept
+	l.SetCacheSize(1)
+	testLocal_zh_CN(t, l)
+	l.SetLanguage("zh_TW")
+	tAssert(t, fs.loads["zh_CN"] == 2 && fs.loads["zh_TW"] == 2, fs.loads)
+	l.SetLanguage("zh_CN")
+	tAssert(t, fs.loads["zh_CN"] == 3, fs.loads)
+	testLocal_zh_CN(t, l)
+}
+
+func TestLocale_cacheMisses(t *testing.T) {
+	l := New("hello", "./examples/locale").SetLanguage("zh_CN")
+	for i := 0; i < 2000; i++ {
+		l.WithLanguage(fmt.Sprintf("xx_%d.UTF-8@m", i))
+	}
+	cache := l.(*_Locale).cache
+	tAssert(t, cache.Len() <= defaultMaxMisses+cache.Size(), cache.Len())
+	testLocal_zh_CN(t, l)
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"sort"
+
+	"github.com/chai2010/gettext-go/mo"
+	"github.com/chai2010/gettext-go/plural"
+	"github.com/chai2010/gettext-go/po"
+)
+
+// Catalog is the loaded messages of a po, mo or json file.
+//
+// It can be queried directly, without a FileSystem, or handed
+// to a Gettexter with NewWithCatalogs.
+type Catalog struct {
+	header po.Header
+	tr     *translator
+}
+
+// NewPoCatalog returns the catalog of a po file.
+//
+// The plural messages with a wrong number of forms are dropped,
+// see Diagnostics. The fuzzy messages are skipped, like msgfmt does.
+func NewPoCatalog(f *po.File) *Catalog {
+	return &Catalog{
+		header: f.MimeHeader,
+		tr:     newPoFileTranslator(f, false),
+	}
+}
+
+// NewPoCatalogUseFuzzy is like NewPoCatalog, but the fuzzy messages
+// are used too (like msgfmt --use-fuzzy), for the preview builds.
+func NewPoCatalogUseFuzzy(f *po.File) *Catalog {
+	return &Catalog{
+		header: f.MimeHeader,
+		tr:     newPoFileTranslator(f, true),
+	}
+}
+
+// NewMoCatalog returns the catalog of a mo file.
+func NewMoCatalog(f *mo.File) *Catalog {
+	return &Catalog{
+		header: moToPoFile(f).MimeHeader,
+		tr:     newMoFileTranslator(f),
+	}
+}
+
+// NewJsonCatalog returns the catalog of the json data of the lang,
+// the json format is the one of a json FileSystem's messages file.
+func NewJsonCatalog(lang string, jsonData []byte) (*Catalog, error) {
+	f, err := loadJsonFile(lang, jsonData)
+	if err != nil {
+		return nil, err
+	}
+	return &Catalog{
+		header: f.MimeHeader,
+		tr:     newJsonFileTranslator(f),
+	}, nil
+}
+
+// Language returns the language of the catalog's header.
+func (p *Catalog) Language() string {
+	return p.header.Language
+}
+
+// Header returns the catalog's header.
+func (p *Catalog) Header() po.Header {
+	h := p.header
+	if h.UnknowFields != nil {
+		h.UnknowFields = make(map[string]string, len(p.header.UnknowFields))
+		for k, v := range p.header.UnknowFields {
+			h.UnknowFields[k] = v
+		}
+	}
+	return h
+}
+
+// Diagnostics returns the Plural-Forms problems of the catalog,
+// see po.File.Validate.
+func (p *Catalog) Diagnostics() []po.Diagnostic {
+	return append([]po.Diagnostic(nil), p.tr.Diagnostics...)
+}
+
+// Len returns the number of messages.
+func (p *Catalog) Len() int {
+	return len(p.tr.MessageMap)
+}
+
+// Messages returns the messages, sorted by msgctxt and msgid.
+func (p *Catalog) Messages() []mo.Message {
+	msgs := make([]mo.Message, 0, len(p.tr.MessageMap))
+	for _, v := range p.tr.MessageMap {
+		msgs = append(msgs, v)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if msgs[i].MsgContext != msgs[j].MsgContext {
+			return msgs[i].MsgContext < msgs[j].MsgContext
+		}
+		return msgs[i].MsgId < msgs[j].MsgId
+	})
+	return msgs
+}
+
+// Lookup returns the message of msgctxt and msgid.
+func (p *Catalog) Lookup(msgctxt, msgid string) (msg mo.Message, ok bool) {
+	msg, ok = p.tr.MessageMap[p.tr.makeMapKey(msgctxt, msgid)]
+	return
+}
+
+func (p *Catalog) Gettext(msgid string) string {
+	return p.tr.PGettext("", msgid)
+}
+
+func (p *Catalog) PGettext(msgctxt, msgid string) string {
+	return p.tr.PGettext(msgctxt, msgid)
+}
+
+func (p *Catalog) NGettext(msgid, msgidPlural string, n int) string {
+	return p.tr.PNGettext("", msgid, msgidPlural, n)
+}
+
+func (p *Catalog) PNGettext(msgctxt, msgid, msgidPlural string, n int) string {
+	return p.tr.PNGettext(msgctxt, msgid, msgidPlural, n)
+}
+
+func (p *Catalog) NGettext64(msgid, msgidPlural string, n int64) string {
+	return p.tr.PNGettext("", msgid, msgidPlural, plural.Int64(n))
+}
+
+func (p *Catalog) PNGettext64(msgctxt, msgid, msgidPlural string, n int64) string {
+	return p.tr.PNGettext(msgctxt, msgid, msgidPlural, plural.Int64(n))
+}
+
+func (p *Catalog) NGettextDecimal(msgid, msgidPlural string, n plural.Operands) string {
+	return p.tr.PNGettextDecimal("", msgid, msgidPlural, n)
+}
+
+func (p *Catalog) PNGettextDecimal(msgctxt, msgid, msgidPlural string, n plural.Operands) string {
+	return p.tr.PNGettextDecimal(msgctxt, msgid, msgidPlural, n)
+}
+
+func (p *Catalog) OGettext(msgid string, n int) string {
+	return p.tr.POGettext("", msgid, n)
+}
+
+func (p *Catalog) POGettext(msgctxt, msgid string, n int) string {
+	return p.tr.POGettext(msgctxt, msgid, n)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"testing"
+
+	"github.com/chai2010/gettext-go/mo"
+	"github.com/chai2010/gettext-go/po"
+)
+
+func TestCatalog(t *testing.T) {
+	f, err := po.LoadFile("./examples/locale/zh_CN/LC_MESSAGES/hello.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewPoCatalog(f)
+	tAssert(t, c.Language() == "zh_CN", c.Language())
+	tAssert(t, c.Header().LanguageTeam == f.MimeHeader.LanguageTeam, c.Header())
+	tAssert(t, c.Len() == len(f.Messages), c.Len(), len(f.Messages))
+
+	msgs := c.Messages()
+	tAssert(t, len(msgs) == c.Len(), len(msgs))
+	for i := 1; i < len(msgs); i++ {
+		a, b := msgs[i-1], msgs[i]
+		tAssert(t, a.MsgContext < b.MsgContext || a.MsgContext == b.MsgContext && a.MsgId < b.MsgId, a, b)
+	}
+	msg, ok := c.Lookup("main.main", "Hello, world!")
+	tAssert(t, ok && msg.MsgStr == "你好, 世界!(ctx:main.main)", msg)
+
+	tAssert(t, c.Gettext("Hello, world!") == "你好, 世界!")
+	tAssert(t, c.PGettext("main.main", "Hello, world!") == "你好, 世界!(ctx:main.main)")
+	tAssert(t, c.Gettext("Bye") == "Bye")
+	testLocal_zh_CN(t, NewWithCatalogs("hello", c).SetLanguage("zh_CN"))
+
+	g := NewWithCatalogs("hello", c).SetLanguage("zh_TW")
+	tAssert(t, g.Gettext("Hello, world!") == "Hello, world!")
+}
+
+func TestCatalog_mo(t *testing.T) {
+	f, err := mo.Load(poToMoData(t, []byte(testTrPluralPoData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewMoCatalog(f)
+	tAssert(t, c.Language() == "zh_CN", c.Language())
+	tAssert(t, c.Header().PluralForms != "", c.Header())
+	tAssert(t, c.NGettext("%d file", "%d files", 0) == "zero")
+	tAssert(t, c.NGettext64("%d file", "%d files", 1) == "one")
+	tAssert(t, c.NGettext("%d file", "%d files", 2) == "many")
+}
+
+func TestCatalog_json(t *testing.T) {
+	c, err := NewJsonCatalog("en", []byte(`[
+		{"msgid": "%d file", "msgid_plural": "%d files", "msgstr": ["%d file(en)", "%d files(en)"]},
+		{"msgctxt": "menu", "msgid": "Open", "msgstr": ["Open(en)"]}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tAssert(t, c.Language() == "en", c.Language())
+	tAssert(t, c.Len() == 2, c.Len())
+	tAssert(t, c.NGettext("%d file", "%d files", 2) == "%d files(en)")
+	tAssert(t, c.PGettext("menu", "Open") == "Open(en)")
+
+	if _, err := NewJsonCatalog("en", []byte(`{`)); err == nil {
+		t.Fatal("expect error")
+	}
+}
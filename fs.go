@@ -8,6 +8,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"fmt"
+	"io"
 )
 
 type FileSystem interface {
@@ -30,35 +31,131 @@ func NewFS(name string, x interface{}) FileSystem {
 		if len(x) == 0 {
 			return OS(name)
 		}
-		if r, err := zip.NewReader(bytes.NewReader(x), int64(len(x))); err == nil {
-			return ZipFS(r, name)
-		}
-		if fs, err := newJson(x, name); err == nil {
+		if fs, err := loadDataFS(x, name, false); err == nil {
 			return fs
 		}
 	case string:
 		if len(x) == 0 {
 			return OS(name)
 		}
-		if r, err := zip.NewReader(bytes.NewReader([]byte(x)), int64(len(x))); err == nil {
-			return ZipFS(r, name)
-		}
-		if fs, err := newJson([]byte(x), name); err == nil {
+		if fs, err := loadDataFS([]byte(x), name, false); err == nil {
 			return fs
 		}
 	case FileSystem:
 		return x
+	case []FileSystem:
+		return LayeredFS(x...)
+	default:
+		if fs, ok := newIOFS(name, x); ok {
+			return fs
+		}
 	}
 
 	return NilFS(name)
 }
 
+// LoadFS is like NewFS, but returns the error instead of a NilFS if
+// x (or the archive or json file of name, if x is nil) can't be loaded,
+// or if the name of the OS FileSystem doesn't exist. The json data is
+// validated, its unknown fields are errors (NewFS ignores them). The zstd
+// compressed data (such as a .tar.zst file) is not supported, see TarFS.
+func LoadFS(name string, x interface{}) (FileSystem, error) {
+	if x == nil {
+		if name != "" {
+			return loadOsFS(name)
+		}
+		return NilFS(name), nil
+	}
+
+	var data []byte
+	switch x := x.(type) {
+	case []byte:
+		data = x
+	case string:
+		data = []byte(x)
+	case FileSystem:
+		return x, nil
+	case []FileSystem:
+		return LayeredFS(x...), nil
+	default:
+		if fs, ok := newIOFS(name, x); ok {
+			return fs, nil
+		}
+		return nil, fmt.Errorf("gettext: %s: unsupported data type %T", name, x)
+	}
+
+	if len(data) == 0 {
+		return loadOsFS(name)
+	}
+	return loadDataFS(data, name, true)
+}
+
+// loadDataFS returns the FileSystem of the zip, tar, tar.gz or json
+// data, the format is detected by its magic bytes. The json data is
+// validated if strict is true, see newJson.
+func loadDataFS(data []byte, name string, strict bool) (FileSystem, error) {
+	if r, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err == nil {
+		return ZipFS(r, name), nil
+	}
+	switch archiveFormat(data) {
+	case "gzip", "tar":
+		return TarFS(bytes.NewReader(data), name)
+	case "zstd":
+		return nil, errZstd(name)
+	}
+	fs, err := newJson(data, name, strict)
+	if err != nil {
+		return nil, fmt.Errorf("gettext: %s: not a zip, tar or json data: %v", name, err)
+	}
+	return fs, nil
+}
+
 func OS(root string) FileSystem {
 	return newOsFS(root)
 }
 
+// OSCategory is like OS, but the messages files of the category
+// ("LC_TIME", ...) are loaded: $(root)/$(lang)/$(category)/$(domain).mo.
+func OSCategory(root, category string) FileSystem {
+	if category == "" {
+		category = "LC_MESSAGES"
+	}
+	return &osFS{root: root, category: category}
+}
+
 func ZipFS(r *zip.Reader, name string) FileSystem {
-	return newZipFS(r, name)
+	return newZipFS(r, name, "LC_MESSAGES")
+}
+
+// ZipFSReaderAt returns the FileSystem of the zip data of r, which
+// has the given size. Only the zip's directory is read, the files are
+// read from r when they are loaded.
+func ZipFSReaderAt(r io.ReaderAt, size int64, name string) (FileSystem, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("gettext: %s: %v", name, err)
+	}
+	return newZipFS(zr, name, "LC_MESSAGES"), nil
+}
+
+// OpenZipFS returns the FileSystem of the zip file, see ZipFSReaderAt.
+// Only the zip's directory is read, the file isn't kept open: it's
+// opened again when a file of the zip is loaded, and its directory is
+// read again if its size or modification time is changed.
+func OpenZipFS(path string) (FileSystem, error) {
+	f, r, err := openZipFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fs := newZipFS(r, path, "LC_MESSAGES")
+	fs.file = f
+	return fs, nil
+}
+
+// ZipFSCategory is like ZipFS, but the messages files of the category
+// are loaded, see OSCategory.
+func ZipFSCategory(r *zip.Reader, name, category string) FileSystem {
+	return newZipFS(r, name, category)
 }
 
 func NilFS(name string) FileSystem {
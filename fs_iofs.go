@@ -0,0 +1,113 @@
+// Copyright 2021 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.16
+// +build go1.16
+
+package gettext
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// IOFS returns the FileSystem of an io/fs.FS, such as embed.FS or
+// fstest.MapFS, the name is only used by String. The layout is the
+// same as OS: $(root)/$(lang)/LC_MESSAGES and $(root)/$(lang)/LC_RESOURCE,
+// the root is detected like a zip file's root.
+//
+// Examples:
+//
+//	//go:embed locale
+//	var localeFS embed.FS
+//
+//	g := New("hello", "", IOFS(localeFS, "locale"))
+func IOFS(fsys fs.FS, name string) FileSystem {
+	p := &ioFS{fsys: fsys, name: name}
+	p.root = p.fsRoot()
+	return p
+}
+
+type ioFS struct {
+	fsys fs.FS
+	name string
+	root string
+}
+
+// newIOFS returns the FileSystem of x, if it's an io/fs.FS.
+func newIOFS(name string, x interface{}) (FileSystem, bool) {
+	if fsys, ok := x.(fs.FS); ok {
+		return IOFS(fsys, name), true
+	}
+	return nil, false
+}
+
+var errFoundRoot = errors.New("found root")
+
+// fsRoot returns the parent dir of the first $(lang)/LC_MESSAGES
+// or $(lang)/LC_RESOURCE dir, or ".".
+func (p *ioFS) fsRoot() string {
+	root := "."
+	fs.WalkDir(p.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if s := path.Base(name); s == "LC_MESSAGES" || s == "LC_RESOURCE" {
+			if dir := path.Dir(name); dir != "." {
+				root = path.Dir(dir)
+				return errFoundRoot
+			}
+		}
+		return nil
+	})
+	return root
+}
+
+func (p *ioFS) LocaleList() []string {
+	list, err := fs.ReadDir(p.fsys, p.root)
+	if err != nil {
+		return nil
+	}
+	var locales []string
+	for _, dir := range list {
+		if dir.IsDir() {
+			locales = append(locales, dir.Name())
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func (p *ioFS) LoadMessagesFile(domain, lang, ext string) ([]byte, error) {
+	return fs.ReadFile(p.fsys, path.Join(p.root, lang, "LC_MESSAGES", domain+ext))
+}
+
+func (p *ioFS) LoadResourceFile(domain, lang, name string) ([]byte, error) {
+	return fs.ReadFile(p.fsys, path.Join(p.root, lang, "LC_RESOURCE", domain, name))
+}
+
+func (p *ioFS) listFiles(lang string) (messages, resources []string) {
+	if list, err := fs.ReadDir(p.fsys, path.Join(p.root, lang, "LC_MESSAGES")); err == nil {
+		for _, d := range list {
+			if !d.IsDir() {
+				messages = append(messages, d.Name())
+			}
+		}
+	}
+	rcDir := path.Join(p.root, lang, "LC_RESOURCE")
+	fs.WalkDir(p.fsys, rcDir, func(name string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			resources = append(resources, strings.TrimPrefix(name, rcDir+"/"))
+		}
+		return nil
+	})
+	return
+}
+
+func (p *ioFS) String() string {
+	return "gettext.iofs(" + p.name + ")"
+}
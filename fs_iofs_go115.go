@@ -0,0 +1,13 @@
+// Copyright 2021 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.16
+// +build !go1.16
+
+package gettext
+
+// newIOFS returns false, io/fs is new in Go 1.16.
+func newIOFS(name string, x interface{}) (FileSystem, bool) {
+	return nil, false
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.16
+// +build go1.16
+
+package gettext
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileSystem_iofs(t *testing.T) {
+	fs := IOFS(os.DirFS("./examples"), "examples")
+	tAssert(t, fs.String() == "gettext.iofs(examples)", fs.String())
+	tAssert(t, fs.(*ioFS).root == "locale", fs.(*ioFS).root)
+	testExamplesLocal(t, fs)
+
+	l := New("hello", "examples", os.DirFS("./examples")).SetLanguage("zh_CN")
+	testLocal_zh_CN(t, l)
+	tAssert(t, len(l.Getdata("poems.txt")) > 0)
+}
+
+func TestFileSystem_iofs_map(t *testing.T) {
+	fsys := fstest.MapFS{
+		"de/LC_MESSAGES/hello.po": &fstest.MapFile{Data: []byte(`
+msgid "Hello"
+msgstr "Hallo"
+`)},
+	}
+	if err := fstest.TestFS(fsys, "de/LC_MESSAGES/hello.po"); err != nil {
+		t.Fatal(err)
+	}
+	fs := IOFS(fsys, "map")
+	tAssert(t, fs.(*ioFS).root == ".", fs.(*ioFS).root)
+	tAssert(t, len(fs.LocaleList()) == 1 && fs.LocaleList()[0] == "de", fs.LocaleList())
+
+	l := New("hello", "", fs).SetLanguage("de")
+	tAssert(t, l.Gettext("Hello") == "Hallo", l.Gettext("Hello"))
+}
@@ -5,37 +5,80 @@
 package gettext
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sort"
+	"strings"
 )
 
 type jsonFS struct {
 	name string
-	x    map[string]struct {
-		LC_MESSAGES map[string][]struct {
-			MsgContext  string   `json:"msgctxt"`      // msgctxt context
-			MsgId       string   `json:"msgid"`        // msgid untranslated-string
-			MsgIdPlural string   `json:"msgid_plural"` // msgid_plural untranslated-string-plural
-			MsgStr      []string `json:"msgstr"`       // msgstr translated-string
-		}
-		LC_RESOURCE map[string]map[string]string
-	}
+	x    map[string]jsonLocale // lang => locale, see JSONSchema
+}
+
+// jsonLocale is the messages files and the resource files of a lang.
+type jsonLocale struct {
+	LC_MESSAGES map[string][]jsonMessage     `json:",omitempty"` // "hello.json" => messages
+	LC_RESOURCE map[string]map[string]string `json:",omitempty"` // "hello" => "poems.txt" => data
+
+	// the binary resource files, base64 encoded
+	LC_RESOURCE_BASE64 map[string]map[string][]byte `json:",omitempty"`
+}
+
+// jsonMessage is a message of a json messages file.
+type jsonMessage struct {
+	MsgContext  string   `json:"msgctxt,omitempty"`      // msgctxt context
+	MsgId       string   `json:"msgid"`                  // msgid untranslated-string
+	MsgIdPlural string   `json:"msgid_plural,omitempty"` // msgid_plural untranslated-string-plural
+	MsgStr      []string `json:"msgstr"`                 // msgstr translated-string
 }
 
 func isJsonData() bool {
 	return false
 }
 
-func newJson(jsonData []byte, name string) (*jsonFS, error) {
+// newJson returns the json FileSystem of the data. If strict is true
+// (LoadFS), the data is validated: the unknown fields, the trailing
+// data and the messages files which aren't json ones are errors. NewFS
+// isn't strict, the unknown fields are ignored as json.Unmarshal does.
+func newJson(jsonData []byte, name string, strict bool) (*jsonFS, error) {
 	p := &jsonFS{name: name}
-	if err := json.Unmarshal(jsonData, &p.x); err != nil {
+	if !strict {
+		if err := json.Unmarshal(jsonData, &p.x); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	if err := unmarshalJsonStrict(jsonData, &p.x); err != nil {
 		return nil, err
 	}
+	for lang, v := range p.x {
+		for name := range v.LC_MESSAGES {
+			if !strings.HasSuffix(name, ".json") {
+				return nil, fmt.Errorf("gettext: %s/LC_MESSAGES/%s: not a json messages file", lang, name)
+			}
+		}
+	}
 
 	return p, nil
 }
 
+// unmarshalJsonStrict is like json.Unmarshal, but the unknown fields
+// and the trailing data are errors.
+func unmarshalJsonStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("gettext: invalid data after the json value")
+	}
+	return nil
+}
+
 func (p *jsonFS) LocaleList() []string {
 	var ss []string
 	for lang := range p.x {
@@ -55,12 +98,32 @@ func (p *jsonFS) LoadMessagesFile(domain, lang, ext string) ([]byte, error) {
 }
 func (p *jsonFS) LoadResourceFile(domain, lang, name string) ([]byte, error) {
 	if v, ok := p.x[lang]; ok {
-		if v, ok := v.LC_RESOURCE[domain]; ok {
-			return []byte(v[name]), nil
+		if v, ok := v.LC_RESOURCE[domain][name]; ok {
+			return []byte(v), nil
+		}
+		if v, ok := v.LC_RESOURCE_BASE64[domain][name]; ok {
+			return v, nil
 		}
 	}
 	return nil, fmt.Errorf("not found")
 }
+func (p *jsonFS) listFiles(lang string) (messages, resources []string) {
+	for name := range p.x[lang].LC_MESSAGES {
+		messages = append(messages, name)
+	}
+	for domain, files := range p.x[lang].LC_RESOURCE {
+		for name := range files {
+			resources = append(resources, domain+"/"+name)
+		}
+	}
+	for domain, files := range p.x[lang].LC_RESOURCE_BASE64 {
+		for name := range files {
+			resources = append(resources, domain+"/"+name)
+		}
+	}
+	return
+}
+
 func (p *jsonFS) String() string {
-	return "gettext.nilfs(" + p.name + ")"
+	return "gettext.jsonfs(" + p.name + ")"
 }
@@ -0,0 +1,231 @@
+// Copyright 2021 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chai2010/gettext-go/mo"
+	"github.com/chai2010/gettext-go/po"
+)
+
+// JSONSchema is the JSON Schema of the json FileSystem's data:
+//
+//	{
+//		"zh_CN": {
+//			"LC_MESSAGES": {
+//				"hello.json": [
+//					{"msgid": "", "msgstr": ["Language: zh_CN\nPlural-Forms: nplurals=1; plural=0;\n"]},
+//					{"msgctxt": "", "msgid": "Hello", "msgstr": ["你好"]}
+//				]
+//			},
+//			"LC_RESOURCE": {
+//				"hello": {"poems.txt": "..."}
+//			},
+//			"LC_RESOURCE_BASE64": {
+//				"hello": {"favicon.ico": "AAABAAEAEBAAAAEAIABoBAAAFgAAACgAAAAQAAAAIAAAAAEAIAAAAAAAAAQAABILAAASCwAAAAAAAA..."}
+//			}
+//		}
+//	}
+//
+// The header entry (msgid "") is optional, like the header of a po file
+// its Plural-Forms selects the msgstr of a plural message, the plural
+// formula is the standard one of the lang if it's missing. The resource
+// files which aren't UTF-8 text are base64 encoded, in LC_RESOURCE_BASE64.
+const JSONSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"title": "gettext-go json FileSystem",
+	"type": "object",
+	"additionalProperties": {"$ref": "#/definitions/locale"},
+	"definitions": {
+		"locale": {
+			"type": "object",
+			"properties": {
+				"LC_MESSAGES": {
+					"type": "object",
+					"propertyNames": {"pattern": "\\.json$"},
+					"additionalProperties": {
+						"type": "array",
+						"items": {"$ref": "#/definitions/message"}
+					}
+				},
+				"LC_RESOURCE": {
+					"type": "object",
+					"additionalProperties": {
+						"type": "object",
+						"additionalProperties": {"type": "string"}
+					}
+				},
+				"LC_RESOURCE_BASE64": {
+					"type": "object",
+					"additionalProperties": {
+						"type": "object",
+						"additionalProperties": {"type": "string", "contentEncoding": "base64"}
+					}
+				}
+			},
+			"additionalProperties": false
+		},
+		"message": {
+			"type": "object",
+			"properties": {
+				"msgctxt": {"type": "string"},
+				"msgid": {"type": "string"},
+				"msgid_plural": {"type": "string"},
+				"msgstr": {"type": "array", "items": {"type": "string"}}
+			},
+			"required": ["msgid"],
+			"additionalProperties": false
+		}
+	}
+}
+`
+
+// fsLister is implemented by the FileSystems which can list their files,
+// see ExportJSON.
+type fsLister interface {
+	// listFiles returns the messages files ("hello.po") and the
+	// resource files ("hello/poems.txt") of the lang.
+	listFiles(lang string) (messages, resources []string)
+}
+
+// ExportJSON returns the json data of the FileSystem's messages and
+// resource files, for the json FileSystem (see JSONSchema). The OS,
+// zip, tar, json, io/fs and layered FileSystems can be exported.
+//
+// A po, mo or json messages file is converted to a json one, the file
+// used by a Gettexter wins if a domain has more than one. The fuzzy
+// messages are skipped, the Language and Plural-Forms of the header
+// are kept.
+//
+// Examples:
+//
+//	data, err := ExportJSON(OS("locale"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	g := New("hello", "locale.json", data)
+func ExportJSON(fs FileSystem) ([]byte, error) {
+	lister, ok := fs.(fsLister)
+	if !ok {
+		return nil, fmt.Errorf("gettext: ExportJSON: can't list the files of %v", fs)
+	}
+
+	bundle := make(map[string]jsonLocale)
+	for _, lang := range fs.LocaleList() {
+		var (
+			locale              jsonLocale
+			messages, resources = lister.listFiles(lang)
+			domains             = make(map[string]bool)
+		)
+		for _, name := range messages {
+			switch ext := path.Ext(name); ext {
+			case ".po", ".mo", ".json":
+				domains[strings.TrimSuffix(name, ext)] = true
+			}
+		}
+		for domain := range domains {
+			msgs, err := exportJsonMessages(fs, domain, lang)
+			if err != nil {
+				return nil, err
+			}
+			if locale.LC_MESSAGES == nil {
+				locale.LC_MESSAGES = make(map[string][]jsonMessage)
+			}
+			locale.LC_MESSAGES[domain+".json"] = msgs
+		}
+		for _, name := range resources {
+			idx := strings.Index(name, "/")
+			if idx < 0 {
+				continue
+			}
+			domain, name := name[:idx], name[idx+1:]
+			data, err := fs.LoadResourceFile(domain, lang, name)
+			if err != nil {
+				return nil, fmt.Errorf("gettext: ExportJSON: %s/%s/%s: %v", lang, domain, name, err)
+			}
+			if !utf8.Valid(data) {
+				if locale.LC_RESOURCE_BASE64 == nil {
+					locale.LC_RESOURCE_BASE64 = make(map[string]map[string][]byte)
+				}
+				if locale.LC_RESOURCE_BASE64[domain] == nil {
+					locale.LC_RESOURCE_BASE64[domain] = make(map[string][]byte)
+				}
+				locale.LC_RESOURCE_BASE64[domain][name] = data
+				continue
+			}
+			if locale.LC_RESOURCE == nil {
+				locale.LC_RESOURCE = make(map[string]map[string]string)
+			}
+			if locale.LC_RESOURCE[domain] == nil {
+				locale.LC_RESOURCE[domain] = make(map[string]string)
+			}
+			locale.LC_RESOURCE[domain][name] = string(data)
+		}
+		if locale.LC_MESSAGES != nil || locale.LC_RESOURCE != nil || locale.LC_RESOURCE_BASE64 != nil {
+			bundle[lang] = locale
+		}
+	}
+	return json.MarshalIndent(bundle, "", "\t")
+}
+
+// exportJsonMessages returns the messages of the first po, mo or
+// json file of the domain and lang, sorted by msgctxt and msgid,
+// after the header entry.
+func exportJsonMessages(fs FileSystem, domain, lang string) ([]jsonMessage, error) {
+	for _, ext := range []string{".po", ".mo", ".json"} {
+		data, err := fs.LoadMessagesFile(domain, lang, ext)
+		if err != nil {
+			continue
+		}
+
+		var c *Catalog
+		switch ext {
+		case ".po":
+			var f *po.File
+			if f, err = po.Load(data); err == nil {
+				c = NewPoCatalog(f)
+			}
+		case ".mo":
+			var f *mo.File
+			if f, err = mo.Load(data); err == nil {
+				c = NewMoCatalog(f)
+			}
+		case ".json":
+			c, err = NewJsonCatalog(lang, data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gettext: ExportJSON: %s/%s%s: %v", lang, domain, ext, err)
+		}
+
+		msgs := []jsonMessage{}
+		if h := c.Header(); h.PluralForms != "" {
+			if h.Language == "" {
+				h.Language = lang
+			}
+			msgs = append(msgs, jsonMessage{MsgStr: []string{
+				"Language: " + h.Language + "\n" + "Plural-Forms: " + h.PluralForms + "\n",
+			}})
+		}
+		for _, v := range c.Messages() {
+			msg := jsonMessage{
+				MsgContext:  v.MsgContext,
+				MsgId:       v.MsgId,
+				MsgIdPlural: v.MsgIdPlural,
+				MsgStr:      []string{v.MsgStr},
+			}
+			if v.MsgIdPlural != "" {
+				msg.MsgStr = append([]string(nil), v.MsgStrPlural...)
+			}
+			msgs = append(msgs, msg)
+		}
+		return msgs, nil
+	}
+	return nil, fmt.Errorf("gettext: ExportJSON: %s/%s: not found", lang, domain)
+}
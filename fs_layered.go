@@ -0,0 +1,98 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LayeredFS returns the FileSystem of the stacked layers, the first
+// layer is the top one.
+//
+// The messages are looked up in the catalog of the top layer first,
+// then in the catalogs of the lower layers, message by message. The
+// files (LoadMessagesFile and LoadResourceFile) are loaded from the
+// top layer which has them, and LocaleList returns the union.
+//
+// Examples:
+//
+//	// the embedded translations, overridden by the files on disk
+//	fs := LayeredFS(OS("/etc/myapp/locale"), NewFS("locale.zip", zipData))
+//	g := New("hello", "", fs)
+func LayeredFS(layers ...FileSystem) FileSystem {
+	return &layeredFS{layers: append([]FileSystem(nil), layers...)}
+}
+
+type layeredFS struct {
+	layers []FileSystem
+}
+
+// fsLayers returns the layers of the FileSystem, the nested
+// layered ones are flattened.
+func fsLayers(fs FileSystem) []FileSystem {
+	x, ok := fs.(*layeredFS)
+	if !ok {
+		return []FileSystem{fs}
+	}
+	var layers []FileSystem
+	for _, fs := range x.layers {
+		layers = append(layers, fsLayers(fs)...)
+	}
+	return layers
+}
+
+func (p *layeredFS) LocaleList() []string {
+	ssMap := make(map[string]bool)
+	for _, fs := range p.layers {
+		for _, s := range fs.LocaleList() {
+			ssMap[s] = true
+		}
+	}
+	var locales = make([]string, 0, len(ssMap))
+	for s := range ssMap {
+		locales = append(locales, s)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func (p *layeredFS) LoadMessagesFile(domain, lang, ext string) ([]byte, error) {
+	for _, fs := range p.layers {
+		if data, err := fs.LoadMessagesFile(domain, lang, ext); err == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (p *layeredFS) LoadResourceFile(domain, lang, name string) ([]byte, error) {
+	for _, fs := range p.layers {
+		if data, err := fs.LoadResourceFile(domain, lang, name); err == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (p *layeredFS) listFiles(lang string) (messages, resources []string) {
+	for _, fs := range p.layers {
+		if fs, ok := fs.(fsLister); ok {
+			ms, rs := fs.listFiles(lang)
+			messages = append(messages, ms...)
+			resources = append(resources, rs...)
+		}
+	}
+	return
+}
+
+func (p *layeredFS) String() string {
+	names := make([]string, len(p.layers))
+	for i, fs := range p.layers {
+		names[i] = fs.String()
+	}
+	return "gettext.layeredfs(" + strings.Join(names, ", ") + ")"
+}
@@ -5,40 +5,83 @@
 package gettext
 
 import (
-	"archive/zip"
-	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 )
 
 type osFS struct {
-	root string
+	root     string
+	category string // "LC_MESSAGES", see OSCategory
 }
 
 func newOsFS(root string) FileSystem {
-	// locale zip file
+	// locale zip, tar, tar.gz or json file
 	if fi, err := os.Stat(root); err == nil && !fi.IsDir() {
-		if strings.HasSuffix(strings.ToLower(root), ".zip") {
-			if x, err := ioutil.ReadFile(root); err == nil {
-				if r, err := zip.NewReader(bytes.NewReader(x), int64(len(x))); err == nil {
-					return ZipFS(r, root)
-				}
-			}
-		}
-		if strings.HasSuffix(strings.ToLower(root), ".json") {
-			if x, err := ioutil.ReadFile(root); err == nil {
-				if fs, err := newJson(x, root); err == nil {
-					return fs
-				}
-			}
+		if fs, err := loadFileFS(root, false); err == nil {
+			return fs
 		}
 	}
 
 	// locale dir
-	return &osFS{root: root}
+	return &osFS{root: root, category: "LC_MESSAGES"}
+}
+
+// loadOsFS is like newOsFS, but returns the error if the root
+// doesn't exist or its archive or json file can't be loaded.
+func loadOsFS(root string) (FileSystem, error) {
+	fi, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("gettext: %v", err)
+	}
+	if fi.IsDir() {
+		return &osFS{root: root, category: "LC_MESSAGES"}, nil
+	}
+	return loadFileFS(root, true)
+}
+
+// loadFileFS returns the FileSystem of the zip, tar, tar.gz or json
+// file, the format is detected by its magic bytes. The json file is
+// validated if strict is true, see newJson.
+func loadFileFS(path string, strict bool) (FileSystem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("gettext: %v", err)
+	}
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	f.Close()
+
+	switch archiveFormat(head[:n]) {
+	case "zip":
+		return OpenZipFS(path)
+	case "gzip", "tar":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("gettext: %v", err)
+		}
+		defer f.Close()
+		return TarFS(f, path)
+	case "zstd":
+		return nil, errZstd(path)
+	}
+
+	if fs, err := OpenZipFS(path); err == nil {
+		return fs, nil // a zip file with a prefix, such as an exe
+	}
+	x, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gettext: %v", err)
+	}
+	fs, err := newJson(x, path, strict)
+	if err != nil {
+		return nil, fmt.Errorf("gettext: %s: not a dir, zip, tar or json file", path)
+	}
+	return fs, nil
 }
 
 func (p *osFS) LocaleList() []string {
@@ -61,6 +104,9 @@ func (p *osFS) LocaleList() []string {
 }
 
 func (p *osFS) LoadMessagesFile(domain, locale, ext string) ([]byte, error) {
+	if !isLanguageName(domain) || !isLanguageName(locale) || !isResourceName(domain+ext) {
+		return nil, os.ErrNotExist
+	}
 	trName := p.makeMessagesFileName(domain, locale, ext)
 	rcData, err := ioutil.ReadFile(trName)
 	if err != nil {
@@ -70,6 +116,9 @@ func (p *osFS) LoadMessagesFile(domain, locale, ext string) ([]byte, error) {
 }
 
 func (p *osFS) LoadResourceFile(domain, locale, name string) ([]byte, error) {
+	if !isLanguageName(domain) || !isLanguageName(locale) || !isResourceName(name) {
+		return nil, os.ErrNotExist
+	}
 	rcName := p.makeResourceFileName(domain, locale, name)
 	rcData, err := ioutil.ReadFile(rcName)
 	if err != nil {
@@ -78,12 +127,43 @@ func (p *osFS) LoadResourceFile(domain, locale, name string) ([]byte, error) {
 	return rcData, nil
 }
 
+func (p *osFS) listFiles(lang string) (messages, resources []string) {
+	if list, err := ioutil.ReadDir(filepath.Join(p.root, lang, p.category)); err == nil {
+		for _, fi := range list {
+			if !fi.IsDir() {
+				messages = append(messages, fi.Name())
+			}
+		}
+	}
+	rcDir := filepath.Join(p.root, lang, "LC_RESOURCE")
+	filepath.Walk(rcDir, func(path string, fi os.FileInfo, err error) error {
+		if err == nil && !fi.IsDir() {
+			if name, err := filepath.Rel(rcDir, path); err == nil {
+				resources = append(resources, filepath.ToSlash(name))
+			}
+		}
+		return nil
+	})
+	return
+}
+
 func (p *osFS) String() string {
 	return "gettext.localfs(" + p.root + ")"
 }
 
+// isResourceName reports whether the resource name is a relative path
+// in the LC_RESOURCE dir, it may have "/" but no ".." elements.
+func isResourceName(name string) bool {
+	for _, s := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if s == ".." {
+			return false
+		}
+	}
+	return name != "" && !strings.ContainsAny(name, "\x00")
+}
+
 func (p *osFS) makeMessagesFileName(domain, lang, ext string) string {
-	return fmt.Sprintf("%s/%s/LC_MESSAGES/%s%s", p.root, lang, domain, ext)
+	return fmt.Sprintf("%s/%s/%s/%s%s", p.root, lang, p.category, domain, ext)
 }
 
 func (p *osFS) makeResourceFileName(domain, lang, name string) string {
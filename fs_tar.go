@@ -0,0 +1,156 @@
+// Copyright 2021 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+type tarFS struct {
+	root  string
+	name  string
+	names []string          // the file names, in the archive's order
+	files map[string][]byte // name => data
+}
+
+// TarFS returns the FileSystem of the tar or gzip compressed tar data
+// of r, the format is detected by its magic bytes. The files are read
+// into memory, the root is detected like a zip file's root.
+//
+// The zstd compressed tar (.tar.zst) is not supported, the standard
+// library has no zstd decoder: an error is returned for its data, it
+// must be decompressed (or recompressed with gzip) before.
+func TarFS(r io.Reader, name string) (FileSystem, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4)
+	switch archiveFormat(head) {
+	case "gzip":
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("gettext: %s: %v", name, err)
+		}
+		defer zr.Close()
+		return newTarFS(zr, name)
+	case "zstd":
+		return nil, errZstd(name)
+	}
+	return newTarFS(br, name)
+}
+
+// errZstd returns the error of the zstd compressed data, see TarFS.
+func errZstd(name string) error {
+	return fmt.Errorf("gettext: %s: zstd compressed data is not supported, use a tar, tar.gz or zip file", name)
+}
+
+func newTarFS(r io.Reader, name string) (*tarFS, error) {
+	p := &tarFS{name: name, files: make(map[string][]byte)}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gettext: %s: %v", name, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("gettext: %s: %v", name, err)
+		}
+		fileName := strings.TrimPrefix(hdr.Name, "./")
+		if _, ok := p.files[fileName]; !ok {
+			p.names = append(p.names, fileName)
+		}
+		p.files[fileName] = data
+	}
+
+	p.root = archiveRoot(p.names, "LC_MESSAGES", p.tarName())
+	return p, nil
+}
+
+// archiveFormat returns the archive format of the data's magic bytes,
+// "zip", "gzip", "zstd", "tar" or "".
+func archiveFormat(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return "zip"
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return "gzip"
+	case bytes.HasPrefix(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return "zstd"
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return "tar"
+	}
+	return ""
+}
+
+func (p *tarFS) tarName() string {
+	name := p.name
+	if x := strings.LastIndexAny(name, `\/`); x != -1 {
+		name = name[x+1:]
+	}
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar"} {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+	return name
+}
+
+func (p *tarFS) LocaleList() []string {
+	var locals []string
+	for s := range archiveLocales(p.names, "LC_MESSAGES") {
+		locals = append(locals, s)
+	}
+	sort.Strings(locals)
+	return locals
+}
+
+func (p *tarFS) LoadMessagesFile(domain, lang, ext string) ([]byte, error) {
+	return p.readFile(fmt.Sprintf("%s/%s/LC_MESSAGES/%s%s", p.root, lang, domain, ext))
+}
+
+func (p *tarFS) LoadResourceFile(domain, lang, name string) ([]byte, error) {
+	return p.readFile(fmt.Sprintf("%s/%s/LC_RESOURCE/%s/%s", p.root, lang, domain, name))
+}
+
+func (p *tarFS) readFile(name string) ([]byte, error) {
+	if data, ok := p.files[name]; ok {
+		return append([]byte(nil), data...), nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (p *tarFS) listFiles(lang string) (messages, resources []string) {
+	var (
+		msgDir = fmt.Sprintf("%s/%s/LC_MESSAGES/", p.root, lang)
+		rcDir  = fmt.Sprintf("%s/%s/LC_RESOURCE/", p.root, lang)
+	)
+	for _, name := range p.names {
+		if s := strings.TrimPrefix(name, msgDir); s != name && !strings.Contains(s, "/") {
+			messages = append(messages, s)
+		}
+		if s := strings.TrimPrefix(name, rcDir); s != name {
+			resources = append(resources, s)
+		}
+	}
+	return
+}
+
+func (p *tarFS) String() string {
+	return "gettext.tarfs(" + p.name + ")"
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeTestTar returns the tar data of ./examples/locale, in "locale/".
+func makeTestTar(t *testing.T, gz bool) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := filepath.Walk("./examples/locale", func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel("./examples", path)
+		hdr := &tar.Header{Name: filepath.ToSlash(name), Mode: 0644, Size: int64(len(data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !gz {
+		return buf.Bytes()
+	}
+
+	var zbuf bytes.Buffer
+	zw := gzip.NewWriter(&zbuf)
+	zw.Write(buf.Bytes())
+	zw.Close()
+	return zbuf.Bytes()
+}
+
+func TestFileSystem_tar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gettext-tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, gz := range []bool{false, true} {
+		data := makeTestTar(t, gz)
+
+		fs := NewFS("locale.tar", data)
+		tAssert(t, fs.String() == "gettext.tarfs(locale.tar)", fs.String())
+		tAssert(t, fs.(*tarFS).root == "locale", fs.(*tarFS).root)
+		testExamplesLocal(t, fs)
+		l := New("hello", "", fs).SetLanguage("zh_CN")
+		testLocal_zh_CN(t, l)
+		tAssert(t, len(l.Getdata("poems.txt")) > 0)
+
+		// detected by the magic bytes, not the extension
+		path := filepath.Join(dir, "locale.bin")
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		fs, err = LoadFS(path, nil)
+		tAssert(t, err == nil, err)
+		testExamplesLocal(t, fs)
+		testLocal_zh_CN(t, New("hello", path).SetLanguage("zh_CN"))
+
+		_, err = ExportJSON(fs)
+		tAssert(t, err == nil, err)
+	}
+
+	_, err = LoadFS("bad.tar.gz", []byte{0x1f, 0x8b, 0})
+	tAssert(t, err != nil)
+
+	// zstd isn't supported, the error tells it
+	zst := []byte{0x28, 0xb5, 0x2f, 0xfd, 0}
+	_, err = LoadFS("locale.tar.zst", zst)
+	tAssert(t, err != nil && strings.Contains(err.Error(), "zstd"), err)
+	_, err = TarFS(bytes.NewReader(zst), "locale.tar.zst")
+	tAssert(t, err != nil && strings.Contains(err.Error(), "zstd"), err)
+	path := filepath.Join(dir, "locale.tar.zst")
+	if err := ioutil.WriteFile(path, zst, 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadFS(path, nil)
+	tAssert(t, err != nil && strings.Contains(err.Error(), "zstd"), err)
+}
@@ -5,7 +5,16 @@
 package gettext
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestFileSystem_os(t *testing.T) {
@@ -29,3 +38,240 @@ func testExamplesLocal(t *testing.T, fs FileSystem) {
 	tAssert(t, localeList[1] == "zh_CN")
 	tAssert(t, localeList[2] == "zh_TW")
 }
+
+func TestFileSystem_layered(t *testing.T) {
+	top := mapFS{
+		"hello/zh_CN.po": `
+msgid "Hello, world!"
+msgstr "你好, 世界!(override)"
+`,
+	}
+	base := NewFS("./examples/locale.zip", nil)
+	fs := LayeredFS(top, base)
+	tAssert(t, fs.String() == "gettext.layeredfs(mapfs, gettext.zipfs(./examples/locale.zip))", fs.String())
+	testExamplesLocal(t, fs)
+
+	l := New("hello", "", []FileSystem{fs, NilFS("")}).SetLanguage("zh_CN")
+	tAssert(t, len(fsLayers(l.FileSystem())) == 3)
+
+	// the messages missing in the top layer are looked up in the base
+	got := l.Gettext("Hello, world!")
+	tAssert(t, got == "你好, 世界!(override)", got)
+	got = l.PGettext("main.main", "Hello, world!")
+	tAssert(t, got == "你好, 世界!(ctx:main.main)", got)
+}
+
+func TestLoadFS(t *testing.T) {
+	for _, v := range []struct {
+		name string
+		x    interface{}
+		ok   bool
+	}{
+		{"./examples/locale", nil, true},
+		{"./examples/locale.zip", nil, true},
+		{"./examples/locale.zip", "", true},
+		{"./examples/hello.go", nil, false},
+		{"./examples/none", nil, false},
+		{"bad.zip", []byte("PK bad"), false},
+		{"bad.json", `{"zh_CN": 1}`, false},
+		{"bad", 42, false},
+		{"", nil, true},
+	} {
+		fs, err := LoadFS(v.name, v.x)
+		tAssert(t, (err == nil) == v.ok, v.name, err)
+		if err == nil {
+			tAssert(t, fs != nil, v.name)
+		}
+	}
+}
+
+func TestFileSystem_category(t *testing.T) {
+	fs := OSCategory("./examples/locale", "LC_RESOURCE")
+	_, err := fs.LoadMessagesFile("hello", "zh_CN", "/poems.txt")
+	tAssert(t, err == nil, err)
+
+	fs = OSCategory("./examples/locale", "")
+	_, err = fs.LoadMessagesFile("hello", "zh_CN", ".po")
+	tAssert(t, err == nil, err)
+}
+
+func TestFileSystem_json(t *testing.T) {
+	for _, src := range []FileSystem{
+		OS("./examples/locale"),
+		NewFS("./examples/locale.zip", nil),
+	} {
+		data, err := ExportJSON(src)
+		if err != nil {
+			t.Fatal(src, err)
+		}
+		fs, err := LoadFS("locale.json", data)
+		if err != nil {
+			t.Fatal(src, err)
+		}
+		tAssert(t, fs.String() == "gettext.jsonfs(locale.json)", fs.String())
+		testExamplesLocal(t, fs)
+
+		l := New("hello", "", fs).SetLanguage("zh_CN")
+		testLocal_zh_CN(t, l)
+		rc, _ := src.LoadResourceFile("hello", "zh_CN", "poems.txt")
+		tAssert(t, string(l.Getdata("poems.txt")) == string(rc))
+
+		// round trip
+		data2, err := ExportJSON(fs)
+		tAssert(t, err == nil && string(data2) == string(data), err)
+	}
+
+	var schema interface{}
+	tAssert(t, json.Unmarshal([]byte(JSONSchema), &schema) == nil)
+
+	for _, s := range []string{
+		`{"zh_CN": {"LC_MESSAGE": {}}}`,
+		`{"zh_CN": {"LC_MESSAGES": {"hello.po": []}}}`,
+		`{"zh_CN": {"LC_MESSAGES": {"hello.json": [{"msgid": "a", "msgstr_plural": []}]}}}`,
+		`{"zh_CN": {}} {}`,
+	} {
+		_, err := LoadFS("bad.json", s)
+		tAssert(t, err != nil, s)
+	}
+
+	// NewFS ignores the unknown fields, as it did before LoadFS
+	s := `{"zh_CN": {"LC_MESSAGES": {"hello.json": [
+		{"msgid": "Hello, world!", "msgstr": ["你好, 世界!"], "comment": "x"}
+	]}, "LC_TIME": {}}}`
+	fs := NewFS("old.json", s)
+	tAssert(t, fs.String() == "gettext.jsonfs(old.json)", fs.String())
+	tAssert(t, len(fs.LocaleList()) == 1, fs.LocaleList())
+	l := New("hello", "", fs).SetLanguage("zh_CN")
+	tAssert(t, l.Gettext("Hello, world!") == "你好, 世界!", l.Gettext("Hello, world!"))
+	_, err := LoadFS("old.json", s)
+	tAssert(t, err != nil)
+
+	_, err = ExportJSON(mapFS{})
+	tAssert(t, err != nil)
+}
+
+func TestFileSystem_jsonPluralForms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gettext-json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "ja", "LC_MESSAGES"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// ja has 1 form in the table
+	err = ioutil.WriteFile(filepath.Join(dir, "ja", "LC_MESSAGES", "hello.po"), []byte(`
+msgid ""
+msgstr ""
+"Language: ja\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "one"
+msgstr[1] "many"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := OS(dir)
+	data, err := ExportJSON(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := LoadFS("locale.json", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1 := New("hello", "", src).SetLanguage("ja")
+	g2 := New("hello", "", fs).SetLanguage("ja")
+	for _, n := range []int{0, 1, 2, 5} {
+		a, b := g1.NGettext("%d file", "%d files", n), g2.NGettext("%d file", "%d files", n)
+		tAssert(t, a == b, n, a, b)
+	}
+	tAssert(t, g2.NGettext("%d file", "%d files", 5) == "many")
+
+	c, err := NewJsonCatalog("ja", []byte(`[{"msgid": "", "msgstr": ["Plural-Forms: nplurals=2; plural=(n != 1);\n"]}]`))
+	tAssert(t, err == nil && c.Header().PluralForms == "nplurals=2; plural=(n != 1);", err)
+	tAssert(t, c.Language() == "ja" && c.Len() == 0)
+}
+
+// makeTestZip returns the zip data of $(root)/de/LC_MESSAGES/hello.po,
+// which translates "Hello" to msgstr.
+func makeTestZip(t *testing.T, root, msgstr string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(root + "/de/LC_MESSAGES/hello.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(w, "msgid \"Hello\"\nmsgstr %q\n", msgstr)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFileSystem_zipReaderAt(t *testing.T) {
+	fs, err := OpenZipFS("./examples/locale.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tAssert(t, fs.String() == "gettext.zipfs(./examples/locale.zip)", fs.String())
+	tAssert(t, len(fs.(*zipFS).files) == len(fs.(*zipFS).r.File))
+	testExamplesLocal(t, fs)
+	testLocal_zh_CN(t, New("hello", "", fs).SetLanguage("zh_CN"))
+	tAssert(t, fs.(*zipFS).file.f == nil) // closed
+
+	_, err = fs.LoadMessagesFile("hello", "zh_CN", ".txt")
+	tAssert(t, err != nil)
+	tAssert(t, New("hello", "./examples/locale.zip").FileSystem().(*zipFS).file.f == nil)
+
+	// a changed file, its directory is read again
+	dir, err := ioutil.TempDir("", "gettext-zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "locale.zip")
+	writeZip := func(data []byte, modTime time.Time) {
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	data1, data2 := makeTestZip(t, "a", "Hallo1"), makeTestZip(t, "b", "Hallo2")
+	tAssert(t, len(data1) == len(data2))
+	writeZip(data1, now.Add(-time.Hour))
+	fs, err = OpenZipFS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := fs.LoadMessagesFile("hello", "de", ".po")
+	tAssert(t, err == nil && strings.Contains(string(data), "Hallo1"), err)
+
+	writeZip(data2, now) // same size
+	data, err = fs.LoadMessagesFile("hello", "de", ".po")
+	tAssert(t, err == nil && strings.Contains(string(data), "Hallo2"), err)
+	tAssert(t, fs.(*zipFS).root == "b", fs.(*zipFS).root)
+
+	writeZip(data1[:len(data1)/2], now.Add(time.Hour))
+	_, err = fs.LoadMessagesFile("hello", "de", ".po")
+	tAssert(t, err != nil)
+	tAssert(t, fs.LocaleList() == nil)
+
+	writeZip(data1, now.Add(time.Hour))
+	data, err = fs.LoadMessagesFile("hello", "de", ".po")
+	tAssert(t, err == nil && strings.Contains(string(data), "Hallo1"), err)
+	tAssert(t, len(fs.LocaleList()) == 1, fs.LocaleList())
+	tAssert(t, fs.(*zipFS).file.f == nil) // closed
+
+	_, err = ZipFSReaderAt(strings.NewReader("PK bad"), 6, "bad.zip")
+	tAssert(t, err != nil)
+	_, err = OpenZipFS("./examples/none.zip")
+	tAssert(t, err != nil)
+}
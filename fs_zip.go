@@ -8,22 +8,129 @@ import (
 	"archive/zip"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 type zipFS struct {
-	root string
-	name string
-	r    *zip.Reader
+	root     string
+	name     string
+	category string // "LC_MESSAGES", see ZipFSCategory
+	r        *zip.Reader
+	files    map[string]*zip.File // name => file
+	file     *zipFile             // the zip file of OpenZipFS, or nil
 }
 
-func newZipFS(r *zip.Reader, name string) *zipFS {
-	fs := &zipFS{r: r, name: name}
-	fs.root = fs.zipRoot()
+// zipFile is the io.ReaderAt of a zip file, which is opened only
+// while its files are read, see OpenZipFS.
+type zipFile struct {
+	mu      sync.Mutex
+	path    string
+	size    int64
+	modTime time.Time
+	f       *os.File // nil if it's closed
+}
+
+// openZipFile returns the zipFile and the zip.Reader of the zip file,
+// the file is closed after its directory is read.
+func openZipFile(path string) (*zipFile, *zip.Reader, error) {
+	p := &zipFile{path: path}
+	if _, err := p.open(); err != nil {
+		return nil, nil, err
+	}
+	defer p.close()
+
+	r, err := zip.NewReader(p, p.size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gettext: %s: %v", path, err)
+	}
+	return p, r, nil
+}
+
+// open opens the file and locks it until close, changed is true if
+// the file's size or modification time is changed since the last open:
+// its directory must be read again.
+func (p *zipFile) open() (changed bool, err error) {
+	p.mu.Lock()
+	f, err := os.Open(p.path)
+	if err != nil {
+		p.mu.Unlock()
+		return false, fmt.Errorf("gettext: %v", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		p.mu.Unlock()
+		return false, fmt.Errorf("gettext: %v", err)
+	}
+	if fi.Size() != p.size || !fi.ModTime().Equal(p.modTime) {
+		p.size, p.modTime, changed = fi.Size(), fi.ModTime(), true
+	}
+	p.f = f
+	return changed, nil
+}
+
+func (p *zipFile) close() {
+	p.f.Close()
+	p.f = nil
+	p.mu.Unlock()
+}
+
+// ReadAt reads the opened file, it must be called between open and close.
+func (p *zipFile) ReadAt(b []byte, off int64) (int, error) {
+	if p.f == nil {
+		return 0, os.ErrClosed
+	}
+	return p.f.ReadAt(b, off)
+}
+
+func newZipFS(r *zip.Reader, name, category string) *zipFS {
+	if category == "" {
+		category = "LC_MESSAGES"
+	}
+	fs := &zipFS{name: name, category: category}
+	fs.setReader(r)
 	return fs
 }
 
+// setReader sets the zip.Reader and its index: the root and the files.
+func (p *zipFS) setReader(r *zip.Reader) {
+	p.r = r
+	p.root = p.zipRoot()
+	p.files = make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		if _, ok := p.files[f.Name]; !ok {
+			p.files[f.Name] = f
+		}
+	}
+}
+
+// lock opens the zip file of OpenZipFS until unlock is called, the
+// index is rebuilt if the file is changed. It does nothing for the
+// other zip FileSystems.
+func (p *zipFS) lock() (unlock func(), err error) {
+	if p.file == nil {
+		return func() {}, nil
+	}
+	changed, err := p.file.open()
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		r, err := zip.NewReader(p.file, p.file.size)
+		if err != nil {
+			p.file.modTime = time.Time{} // read it again on the next open
+			p.file.close()
+			return nil, fmt.Errorf("gettext: %s: %v", p.file.path, err)
+		}
+		p.setReader(r)
+	}
+	return p.file.close, nil
+}
+
 func (p *zipFS) zipName() string {
 	name := p.name
 	if x := strings.LastIndexAny(name, `\/`); x != -1 {
@@ -34,32 +141,53 @@ func (p *zipFS) zipName() string {
 }
 
 func (p *zipFS) zipRoot() string {
+	return archiveRoot(p.fileNames(), p.category, p.zipName())
+}
+
+func (p *zipFS) fileNames() []string {
+	names := make([]string, len(p.r.File))
+	for i, f := range p.r.File {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// archiveRoot returns the root of the archive's file names,
+// the $(root) of the first $(root)/$(lang)/$(category) name
+// or $(root)/$(lang)/LC_RESOURCE name.
+func archiveRoot(names []string, category, defaultRoot string) string {
 	var somepath string
-	for _, f := range p.r.File {
-		if x := strings.Index(f.Name, "LC_MESSAGES"); x != -1 {
-			somepath = f.Name
+	for _, name := range names {
+		if x := strings.Index(name, category); x != -1 {
+			somepath = name
 		}
-		if x := strings.Index(f.Name, "LC_RESOURCE"); x != -1 {
-			somepath = f.Name
+		if x := strings.Index(name, "LC_RESOURCE"); x != -1 {
+			somepath = name
 		}
 	}
 	if somepath == "" {
-		return p.zipName()
+		return defaultRoot
 	}
 
 	ss := strings.Split(somepath, "/")
 	for i, s := range ss {
 		// $(root)/$(lang)/LC_MESSAGES
 		// $(root)/$(lang)/LC_RESOURCE
-		if (s == "LC_MESSAGES" || s == "LC_RESOURCE") && i >= 2 {
+		if (s == category || s == "LC_RESOURCE") && i >= 2 {
 			return strings.Join(ss[:i-1], "/")
 		}
 	}
 
-	return p.zipName()
+	return defaultRoot
 }
 
 func (p *zipFS) LocaleList() []string {
+	unlock, err := p.lock()
+	if err != nil {
+		return nil
+	}
+	defer unlock()
+
 	var locals []string
 	for s := range p.lsZip(p.r) {
 		locals = append(locals, s)
@@ -69,37 +197,62 @@ func (p *zipFS) LocaleList() []string {
 }
 
 func (p *zipFS) LoadMessagesFile(domain, lang, ext string) ([]byte, error) {
-	trName := p.makeMessagesFileName(domain, lang, ext)
-	for _, f := range p.r.File {
-		if f.Name != trName {
-			continue
-		}
-		rc, err := f.Open()
-		if err != nil {
-			return nil, err
-		}
-		rcData, err := ioutil.ReadAll(rc)
-		rc.Close()
-		return rcData, err
+	unlock, err := p.lock()
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("not found")
+	defer unlock()
+	return p.readFile(p.makeMessagesFileName(domain, lang, ext))
 }
 
 func (p *zipFS) LoadResourceFile(domain, lang, name string) ([]byte, error) {
-	rcName := p.makeResourceFileName(domain, lang, name)
+	unlock, err := p.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return p.readFile(p.makeResourceFileName(domain, lang, name))
+}
+
+// readFile reads the file of the zip, it must be called between
+// lock and unlock.
+func (p *zipFS) readFile(name string) ([]byte, error) {
+	f, ok := p.files[name]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	rcData, err := ioutil.ReadAll(rc)
+	rc.Close()
+	return rcData, err
+}
+
+func (p *zipFS) listFiles(lang string) (messages, resources []string) {
+	unlock, err := p.lock()
+	if err != nil {
+		return nil, nil
+	}
+	defer unlock()
+
+	var (
+		msgDir = fmt.Sprintf("%s/%s/%s/", p.root, lang, p.category)
+		rcDir  = fmt.Sprintf("%s/%s/LC_RESOURCE/", p.root, lang)
+	)
 	for _, f := range p.r.File {
-		if f.Name != rcName {
+		if strings.HasSuffix(f.Name, "/") {
 			continue
 		}
-		rc, err := f.Open()
-		if err != nil {
-			return nil, err
+		if name := strings.TrimPrefix(f.Name, msgDir); name != f.Name && !strings.Contains(name, "/") {
+			messages = append(messages, name)
+		}
+		if name := strings.TrimPrefix(f.Name, rcDir); name != f.Name {
+			resources = append(resources, name)
 		}
-		rcData, err := ioutil.ReadAll(rc)
-		rc.Close()
-		return rcData, err
 	}
-	return nil, fmt.Errorf("not found")
+	return
 }
 
 func (p *zipFS) String() string {
@@ -107,7 +260,7 @@ func (p *zipFS) String() string {
 }
 
 func (p *zipFS) makeMessagesFileName(domain, lang, ext string) string {
-	return fmt.Sprintf("%s/%s/LC_MESSAGES/%s%s", p.root, lang, domain, ext)
+	return fmt.Sprintf("%s/%s/%s/%s%s", p.root, lang, p.category, domain, ext)
 }
 
 func (p *zipFS) makeResourceFileName(domain, lang, name string) string {
@@ -115,10 +268,15 @@ func (p *zipFS) makeResourceFileName(domain, lang, name string) string {
 }
 
 func (p *zipFS) lsZip(r *zip.Reader) map[string]bool {
+	return archiveLocales(p.fileNames(), p.category)
+}
+
+// archiveLocales returns the $(lang) of the archive's file names.
+func archiveLocales(names []string, category string) map[string]bool {
 	ssMap := make(map[string]bool)
-	for _, f := range r.File {
-		if x := strings.Index(f.Name, "LC_MESSAGES"); x != -1 {
-			s := strings.TrimRight(f.Name[:x], `\/`)
+	for _, name := range names {
+		if x := strings.Index(name, category); x != -1 {
+			s := strings.TrimRight(name[:x], `\/`)
 			if x = strings.LastIndexAny(s, `\/`); x != -1 {
 				s = s[x+1:]
 			}
@@ -127,8 +285,8 @@ func (p *zipFS) lsZip(r *zip.Reader) map[string]bool {
 			}
 			continue
 		}
-		if x := strings.Index(f.Name, "LC_RESOURCE"); x != -1 {
-			s := strings.TrimRight(f.Name[:x], `\/`)
+		if x := strings.Index(name, "LC_RESOURCE"); x != -1 {
+			s := strings.TrimRight(name[:x], `\/`)
 			if x = strings.LastIndexAny(s, `\/`); x != -1 {
 				s = s[x+1:]
 			}
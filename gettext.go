@@ -4,10 +4,20 @@
 
 package gettext
 
-import "sync"
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/chai2010/gettext-go/plural"
+	"github.com/chai2010/gettext-go/po"
+)
 
 var (
-	DefaultLanguage string = getDefaultLanguage() // use $(LC_MESSAGES) or $(LANG) or "default"
+	DefaultLanguage  string   = getDefaultLanguage()  // use $(LANGUAGE) or $(LC_ALL) or $(LC_MESSAGES) or $(LANG) or "default"
+	DefaultLanguages []string = getDefaultLanguages() // the $(LANGUAGE) priority list, the default fallbacks
 )
 
 type Gettexter interface {
@@ -19,12 +29,72 @@ type Gettexter interface {
 	GetLanguage() string
 	SetLanguage(lang string) Gettexter
 
+	GetFallbacks() []string
+	SetFallbacks(langs ...string) Gettexter
+
+	// WithLanguage and WithDomain return an immutable view of the
+	// language and the domain, which shares the loaded catalogs.
+	// SetDomain, SetLanguage, SetFallbacks and SetMissingHandler of a
+	// view return a new view. SetCacheSize, SetUseFuzzy and Invalidate
+	// change the shared catalogs: of the Gettexter and all its views.
+	WithLanguage(lang string) Gettexter
+	WithDomain(domain string) Gettexter
+
+	// SetCacheSize limits the number of cached catalogs (a po, mo or json
+	// file of a FileSystem layer), the least recently used ones are dropped.
+	// The languages without a catalog are cached too, at most n of them.
+	// The catalogs of the current domain, language and fallbacks are never
+	// dropped. Zero is unlimited (the default). It's shared by the
+	// Gettexter and its views.
+	SetCacheSize(n int) Gettexter
+
+	// Invalidate drops the cached catalogs of the domain and the lang,
+	// an empty domain or lang matches all. They are loaded again on
+	// the next use, the views keep their catalogs. It's shared by the
+	// Gettexter and its views.
+	Invalidate(domain, lang string)
+
+	// LoadError returns the error of loading the catalog of the domain
+	// and the lang (the current ones if empty), or nil. A missing catalog
+	// isn't an error, a catalog which can't be parsed is a *LoadError.
+	LoadError(domain, lang string) error
+
+	// Diagnostics returns the Plural-Forms problems of the catalogs of
+	// the domain and the lang (the current ones if empty), in the order
+	// of the FileSystem layers, see po.File.Validate. The plural messages
+	// with a wrong number of forms are dropped: they return the msgid or
+	// the msgid_plural, like the missing ones.
+	Diagnostics(domain, lang string) []po.Diagnostic
+
+	// SetUseFuzzy sets whether the fuzzy messages of the po files are
+	// used (like msgfmt --use-fuzzy), the default is false. The po files
+	// are loaded again, the catalogs of NewWithCatalogs are not changed.
+	// It's shared by the Gettexter and its views, the views created
+	// after it use the reloaded catalogs.
+	SetUseFuzzy(useFuzzy bool) Gettexter
+
+	// SetMissingHandler sets the func which is called with the messages
+	// missing in all the catalogs of the fallback chain, once for every
+	// message. A nil fn disables it. See MissingCollector.
+	//
+	// A view returns a new view with the handler, which is kept by its
+	// new views. The views without a handler use the Gettexter's one.
+	SetMissingHandler(fn func(m Missing)) Gettexter
+
 	Gettext(msgid string) string
 	PGettext(msgctxt, msgid string) string
 
 	NGettext(msgid, msgidPlural string, n int) string
 	PNGettext(msgctxt, msgid, msgidPlural string, n int) string
 
+	NGettext64(msgid, msgidPlural string, n int64) string
+	PNGettext64(msgctxt, msgid, msgidPlural string, n int64) string
+	NGettextDecimal(msgid, msgidPlural string, n plural.Operands) string
+	PNGettextDecimal(msgctxt, msgid, msgidPlural string, n plural.Operands) string
+
+	OGettext(msgid string, n int) string
+	POGettext(msgctxt, msgid string, n int) string
+
 	DGettext(domain, msgid string) string
 	DPGettext(domain, msgctxt, msgid string) string
 	DNGettext(domain, msgid, msgidPlural string, n int) string
@@ -39,18 +109,91 @@ func New(domain, path string, data ...interface{}) Gettexter {
 	return newLocale(domain, path, data...)
 }
 
+// NewE is like New, but returns the error if the FileSystem can't be
+// loaded (see LoadFS), or if a catalog of the domain's current languages
+// can't be parsed (see Gettexter.LoadError).
+func NewE(domain, path string, data ...interface{}) (Gettexter, error) {
+	var x interface{}
+	if len(data) > 0 {
+		x = data[0]
+	}
+	fs, err := LoadFS(path, x)
+	if err != nil {
+		return nil, err
+	}
+	p := newLocaleWithFS(domain, fs)
+	for _, lang := range p.view().langs {
+		if err := p.LoadError(p.domain, lang); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// LoadError is the error of a catalog which can't be parsed.
+type LoadError struct {
+	Domain   string
+	Language string
+	File     string // such as "hello_zh_CN.po"
+	FS       string // the FileSystem's String
+	Err      error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("gettext: %s: load %s: %v", e.FS, e.File, e.Err)
+}
+
+// NewWithCatalogs create Interface of the loaded catalogs of the domain,
+// without a FileSystem. The catalog of a language is selected by its
+// Catalog.Language.
+//
+// Examples:
+//
+//	f, _ := po.LoadFile("hello_zh_CN.po")
+//	g := NewWithCatalogs("hello", NewPoCatalog(f)).SetLanguage("zh_CN")
+func NewWithCatalogs(domain string, catalogs ...*Catalog) Gettexter {
+	p := newLocale(domain, "")
+	for _, c := range catalogs {
+		p.catalogs[p.makeTrMapKey(p.domain, c.Language())] = c.tr
+	}
+	p.Invalidate("", "")
+	return p
+}
+
 var defaultGettexter struct {
 	lang   string
 	domain string
 	Gettexter
 }
 
-var defaultMu sync.RWMutex
+// defaultMu guards the writers of defaultGettexter, the readers use
+// defaultCurrent, which is published after every change.
+var defaultMu sync.Mutex
+
+// defaultCurrent is the defaultGettexter.Gettexter, as a gettexterValue.
+var defaultCurrent atomic.Value
+
+type gettexterValue struct {
+	Gettexter
+}
 
 func init() {
 	defaultGettexter.lang = getDefaultLanguage()
 	defaultGettexter.domain = "default"
 	defaultGettexter.Gettexter = newLocale("", "")
+	defaultCurrent.Store(gettexterValue{defaultGettexter.Gettexter})
+}
+
+// defaultLocale returns the bound Gettexter, without locking.
+func defaultLocale() Gettexter {
+	return defaultCurrent.Load().(gettexterValue).Gettexter
+}
+
+// setDefaultLocale sets and publishes the bound Gettexter,
+// defaultMu must be held.
+func setDefaultLocale(g Gettexter) {
+	defaultGettexter.Gettexter = g
+	defaultCurrent.Store(gettexterValue{g})
 }
 
 // BindLocale sets and queries program's domains.
@@ -72,13 +215,38 @@ func BindLocale(g Gettexter) {
 	defaultMu.Lock()
 	defer defaultMu.Unlock()
 
-	if g != nil {
-		defaultGettexter.Gettexter = g
-		defaultGettexter.SetLanguage(defaultGettexter.lang)
-	} else {
-		defaultGettexter.Gettexter = newLocale("", "")
-		defaultGettexter.SetLanguage(defaultGettexter.lang)
+	if g == nil {
+		g = newLocale("", "")
+	}
+	setDefaultLocale(g.SetLanguage(defaultGettexter.lang))
+}
+
+// DefaultLocaleDir is the system's locale dir of BindTextDomain.
+var DefaultLocaleDir = "/usr/share/locale"
+
+// BindTextDomain returns the Gettexter of the domain's messages files in
+// a GNU locale dir, like bindtextdomain(domain, dirname) of C programs.
+// The empty dirname is DefaultLocaleDir.
+//
+// Unlike GNU's bindtextdomain, it binds nothing globally: the package's
+// Gettext and the other Gettexters don't use the dir, only the returned
+// Gettexter does.
+//
+// The languages are the user's locale names ($(LANGUAGE), then $(LC_ALL)
+// or $(LC_MESSAGES) or $(LANG)), with their codesets and modifiers, and
+// they are looked up like GNU's gettext: "sr_RS.UTF-8@latin" tries
+// "sr_RS.UTF-8@latin", "sr_RS.utf8@latin", "sr_RS@latin", ..., "sr".
+//
+// Examples:
+//
+//	g := BindTextDomain("coreutils", "")
+//	fmt.Println(g.Gettext("Try '%s --help' for more information.\n"))
+func BindTextDomain(domain, dirname string) Gettexter {
+	if dirname == "" {
+		dirname = DefaultLocaleDir
 	}
+	langs := parseLocales(os.Getenv, strings.TrimSpace)
+	return newLocaleWithLanguages(domain, OS(dirname), langs[0], langs[1:])
 }
 
 // SetLanguage sets and queries the program's current lang.
@@ -98,10 +266,54 @@ func SetLanguage(lang string) string {
 	defaultMu.Lock()
 	defer defaultMu.Unlock()
 
-	defaultGettexter.SetLanguage(lang)
+	setDefaultLocale(defaultGettexter.SetLanguage(lang))
 	return defaultGettexter.GetLanguage()
 }
 
+// SetFallbacks sets the fallback languages of the current lang.
+//
+// A message missing in the lang's catalog is looked up in the catalogs
+// of its parent languages, then of the fallbacks (and their parents),
+// then of "default". Getdata uses the same chain.
+//
+// Examples:
+//
+//	SetLanguage("de_AT")
+//	SetFallbacks("en") // de_AT => de => en => default
+func SetFallbacks(langs ...string) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	setDefaultLocale(defaultGettexter.SetFallbacks(langs...))
+}
+
+// SetMissingHandler sets the func which is called with the untranslated
+// messages of the program, see Gettexter.SetMissingHandler.
+//
+// Examples:
+//
+//	c := new(MissingCollector)
+//	SetMissingHandler(c.Add)
+func SetMissingHandler(fn func(m Missing)) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	setDefaultLocale(defaultGettexter.SetMissingHandler(fn))
+}
+
+// WithLanguage returns an immutable view of the lang, the program's
+// current lang is not changed. It's safe for concurrent use.
+//
+// Examples:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		g := gettext.WithLanguage("fr")
+//		fmt.Fprintln(w, g.Gettext("Hello, world!"))
+//	}
+func WithLanguage(lang string) Gettexter {
+	return defaultLocale().WithLanguage(lang)
+}
+
 // SetDomain sets and retrieves the current message domain.
 //
 // If the domain is not empty string, set the new domains.
@@ -118,7 +330,7 @@ func SetDomain(domain string) string {
 	defaultMu.Lock()
 	defer defaultMu.Unlock()
 
-	defaultGettexter.SetDomain(domain)
+	setDefaultLocale(defaultGettexter.SetDomain(domain))
 	return defaultGettexter.GetDomain()
 }
 
@@ -133,10 +345,7 @@ func SetDomain(domain string) string {
 //		msg := gettext.Gettext("Hello") // msgctxt is ""
 //	}
 func Gettext(msgid string) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.Gettext(msgid)
+	return defaultLocale().Gettext(msgid)
 }
 
 // Getdata attempt to translate a resource file into the user's native language,
@@ -150,10 +359,7 @@ func Gettext(msgid string) string {
 //		poems := gettext.Getdata("poems.txt")
 //	}
 func Getdata(name string) []byte {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.Getdata(name)
+	return defaultLocale().Getdata(name)
 }
 
 // NGettext attempt to translate a text string into the user's native language,
@@ -168,10 +374,7 @@ func Getdata(name string) []byte {
 //		msg := gettext.NGettext("%d people", "%d peoples", 2)
 //	}
 func NGettext(msgid, msgidPlural string, n int) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.NGettext(msgid, msgidPlural, n)
+	return defaultLocale().NGettext(msgid, msgidPlural, n)
 }
 
 // PGettext attempt to translate a text string into the user's native language,
@@ -183,10 +386,7 @@ func NGettext(msgid, msgidPlural string, n int) string {
 //		msg := gettext.PGettext("gettext-go.example", "Hello") // msgctxt is "gettext-go.example"
 //	}
 func PGettext(msgctxt, msgid string) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.PGettext(msgctxt, msgid)
+	return defaultLocale().PGettext(msgctxt, msgid)
 }
 
 // PNGettext attempt to translate a text string into the user's native language,
@@ -199,10 +399,89 @@ func PGettext(msgctxt, msgid string) string {
 //		msg := gettext.PNGettext("gettext-go.example", "%d people", "%d peoples", 2)
 //	}
 func PNGettext(msgctxt, msgid, msgidPlural string, n int) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
+	return defaultLocale().PNGettext(msgctxt, msgid, msgidPlural, n)
+}
+
+// NGettext64 like NGettext(), but for an int64 number.
+//
+// The numbers which don't fit an int are reduced as GNU's gettext
+// recommends, see plural.Int64.
+//
+// Examples:
+//
+//	func Foo() {
+//		msg := gettext.NGettext64("%d byte", "%d bytes", size)
+//	}
+func NGettext64(msgid, msgidPlural string, n int64) string {
+	return defaultLocale().NGettext64(msgid, msgidPlural, n)
+}
+
+// PNGettext64 like PNGettext(), but for an int64 number.
+//
+// Examples:
+//
+//	func Foo() {
+//		msg := gettext.PNGettext64("gettext-go.example", "%d byte", "%d bytes", size)
+//	}
+func PNGettext64(msgctxt, msgid, msgidPlural string, n int64) string {
+	return defaultLocale().PNGettext64(msgctxt, msgid, msgidPlural, n)
+}
+
+// NGettextDecimal like NGettext(), but for a decimal number, such as a uint64
+// (plural.UintOperands), a float (plural.FloatOperands) or a formatted
+// number (plural.ParseOperands).
+//
+// The integers use the gettext plural formula, the numbers with fraction
+// digits use the CLDR rules of the catalog's language, mapped to the forms of
+// its Plural-Forms formula, see plural.DecimalFormula.
+//
+// Examples:
+//
+//	func Foo() {
+//		n, _ := plural.FloatOperands(1.5, 1)
+//		msg := gettext.NGettextDecimal("%s file", "%s files", n)
+//	}
+func NGettextDecimal(msgid, msgidPlural string, n plural.Operands) string {
+	return defaultLocale().NGettextDecimal(msgid, msgidPlural, n)
+}
+
+// PNGettextDecimal like PNGettext(), but for a decimal number.
+//
+// Examples:
+//
+//	func Foo() {
+//		n, _ := plural.ParseOperands("1.50")
+//		msg := gettext.PNGettextDecimal("gettext-go.example", "%s euro", "%s euros", n)
+//	}
+func PNGettextDecimal(msgctxt, msgid, msgidPlural string, n plural.Operands) string {
+	return defaultLocale().PNGettextDecimal(msgctxt, msgid, msgidPlural, n)
+}
+
+// OGettext attempt to translate a text string into the user's native language,
+// by looking up the appropriate ordinal form ("1st", "2nd", "3rd", ...) of
+// the translation in a message catalog.
+//
+// The ordinal forms are selected with the CLDR ordinal rules of the catalog's
+// language, see po.OrdinalContext for the catalog convention.
+//
+// Examples:
+//
+//	func Foo() {
+//		msg := fmt.Sprintf(gettext.OGettext("%d place", 2), 2) // 2nd place
+//	}
+func OGettext(msgid string, n int) string {
+	return defaultLocale().OGettext(msgid, n)
+}
 
-	return defaultGettexter.PNGettext(msgctxt, msgid, msgidPlural, n)
+// POGettext like OGettext(), but with the msgctxt context.
+//
+// Examples:
+//
+//	func Foo() {
+//		msg := gettext.POGettext("gettext-go.example", "%d place", 2)
+//	}
+func POGettext(msgctxt, msgid string, n int) string {
+	return defaultLocale().POGettext(msgctxt, msgid, n)
 }
 
 // DGettext like Gettext(), but looking up the message in the specified domain.
@@ -213,10 +492,7 @@ func PNGettext(msgctxt, msgid, msgidPlural string, n int) string {
 //		msg := gettext.DGettext("poedit", "Hello")
 //	}
 func DGettext(domain, msgid string) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.DGettext(domain, msgid)
+	return defaultLocale().DGettext(domain, msgid)
 }
 
 // DNGettext like NGettext(), but looking up the message in the specified domain.
@@ -227,10 +503,7 @@ func DGettext(domain, msgid string) string {
 //		msg := gettext.PNGettext("poedit", "gettext-go.example", "%d people", "%d peoples", 2)
 //	}
 func DNGettext(domain, msgid, msgidPlural string, n int) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.DNGettext(domain, msgid, msgidPlural, n)
+	return defaultLocale().DNGettext(domain, msgid, msgidPlural, n)
 }
 
 // DPGettext like PGettext(), but looking up the message in the specified domain.
@@ -241,10 +514,7 @@ func DNGettext(domain, msgid, msgidPlural string, n int) string {
 //		msg := gettext.DPGettext("poedit", "gettext-go.example", "Hello")
 //	}
 func DPGettext(domain, msgctxt, msgid string) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.DPGettext(domain, msgctxt, msgid)
+	return defaultLocale().DPGettext(domain, msgctxt, msgid)
 }
 
 // DPNGettext like PNGettext(), but looking up the message in the specified domain.
@@ -255,10 +525,7 @@ func DPGettext(domain, msgctxt, msgid string) string {
 //		msg := gettext.DPNGettext("poedit", "gettext-go.example", "%d people", "%d peoples", 2)
 //	}
 func DPNGettext(domain, msgctxt, msgid, msgidPlural string, n int) string {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.DPNGettext(domain, msgctxt, msgid, msgidPlural, n)
+	return defaultLocale().DPNGettext(domain, msgctxt, msgid, msgidPlural, n)
 }
 
 // DGetdata like Getdata(), but looking up the resource in the specified domain.
@@ -269,8 +536,5 @@ func DPNGettext(domain, msgctxt, msgid, msgidPlural string, n int) string {
 //		msg := gettext.DGetdata("hello", "poems.txt")
 //	}
 func DGetdata(domain, name string) []byte {
-	defaultMu.RLock()
-	defer defaultMu.RUnlock()
-
-	return defaultGettexter.DGetdata(domain, name)
+	return defaultLocale().DGetdata(domain, name)
 }
@@ -6,6 +6,7 @@ package gettext
 
 import (
 	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 )
@@ -107,6 +108,20 @@ func BenchmarkGettext(b *testing.B) {
 		PGettext(testTexts[0].ctx, testTexts[0].src)
 	}
 }
+
+func BenchmarkGettext_parallel(b *testing.B) {
+	SetLanguage("zh_CN")
+	BindLocale(New("hello", "./examples/locale", nil))
+	SetDomain("hello")
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			PGettext(testTexts[0].ctx, testTexts[0].src)
+		}
+	})
+}
+
 func BenchmarkGettext_Zip(b *testing.B) {
 	SetLanguage("zh_CN")
 	BindLocale(New("hello", "./examples/locale.zip", nil))
@@ -251,3 +266,25 @@ yǒnɡjiéwúqínɡyóu，xiānɡqīmiǎoyúnhàn。
 `,
 	},
 }
+
+func TestBindTextDomain(t *testing.T) {
+	for _, key := range []string{"LANGUAGE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if old, ok := os.LookupEnv(key); ok {
+			defer os.Setenv(key, old)
+		} else {
+			defer os.Unsetenv(key)
+		}
+		os.Unsetenv(key)
+	}
+	os.Setenv("LANG", "zh_CN.UTF-8")
+	defer func(lang string) { DefaultLanguage = lang }(DefaultLanguage)
+	DefaultLanguage = "fr"
+
+	g := BindTextDomain("hello", "./examples/locale")
+	tAssert(t, g.GetLanguage() == "zh_CN.UTF-8", g.GetLanguage())
+	tAssert(t, g.Gettext("Hello, world!") == "你好, 世界!", g.Gettext("Hello, world!"))
+
+	// only the catalogs of the user's languages are loaded
+	_, ok := g.(*_Locale).cache.Peek("hello", "fr")
+	tAssert(t, !ok)
+}
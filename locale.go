@@ -7,31 +7,70 @@ package gettext
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+
+	"github.com/chai2010/gettext-go/plural"
+	"github.com/chai2010/gettext-go/po"
 )
 
+// _Locale is the Gettexter of a FileSystem.
+//
+// The lookups read the current snapshot, an immutable *_View, without
+// locking. The writers (SetLanguage, SetDomain, Invalidate, ...) hold
+// the mutex, and publish a new snapshot.
 type _Locale struct {
 	mutex     sync.Mutex
 	fs        FileSystem
 	lang      string
 	domain    string
-	trMap     map[string]*translator
-	trCurrent *translator
+	fallbacks []string
+	cache     *trCache
+	useFuzzy  bool                   // see SetUseFuzzy
+	catalogs  map[string]*translator // see NewWithCatalogs
+	errors    map[trCacheKey]error   // see LoadError
+	current   atomic.Value           // *_View
+	missing   atomic.Value           // *missingHandler, see SetMissingHandler
+	views     viewCache              // the views of WithLanguage and WithDomain, see cachedView
 }
 
 var _ Gettexter = (*_Locale)(nil)
 
 func newLocale(domain, path string, data ...interface{}) *_Locale {
+	if len(data) > 0 {
+		return newLocaleWithFS(domain, NewFS(path, data[0]))
+	}
+	return newLocaleWithFS(domain, NewFS(path, nil))
+}
+
+func newLocaleWithFS(domain string, fs FileSystem) *_Locale {
+	var fallbacks []string
+	if len(DefaultLanguages) > 1 {
+		fallbacks = DefaultLanguages[1:]
+	}
+	return newLocaleWithLanguages(domain, fs, DefaultLanguage, fallbacks)
+}
+
+// newLocaleWithLanguages is like newLocaleWithFS, but the lang and the
+// fallbacks are given: only their catalogs are loaded.
+func newLocaleWithLanguages(domain string, fs FileSystem, lang string, fallbacks []string) *_Locale {
 	if domain == "" {
 		domain = "default"
 	}
+	if lang == "" {
+		lang = DefaultLanguage
+	}
 	p := &_Locale{
-		lang:   DefaultLanguage,
-		domain: domain,
+		fs:        fs,
+		lang:      lang,
+		domain:    domain,
+		fallbacks: append([]string(nil), fallbacks...),
+		cache:     newTrCache(),
+		catalogs:  make(map[string]*translator),
+		errors:    make(map[trCacheKey]error),
 	}
-	if len(data) > 0 {
-		p.fs = NewFS(path, data[0])
-	} else {
-		p.fs = NewFS(path, nil)
+	p.cache.onRemove = func(key trCacheKey, tr trChain) {
+		delete(p.errors, key)
+		p.removeViews(key, tr)
 	}
 
 	p.syncTrMap()
@@ -46,11 +85,13 @@ func (p *_Locale) FileSystem() FileSystem {
 	return p.fs
 }
 
-func (p *_Locale) GetLanguage() string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
+// view returns the current snapshot.
+func (p *_Locale) view() *_View {
+	return p.current.Load().(*_View)
+}
 
-	return p.lang
+func (p *_Locale) GetLanguage() string {
+	return p.view().lang
 }
 func (p *_Locale) SetLanguage(lang string) Gettexter {
 	p.mutex.Lock()
@@ -69,9 +110,7 @@ func (p *_Locale) SetLanguage(lang string) Gettexter {
 }
 
 func (p *_Locale) GetDomain() string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	return p.domain
+	return p.view().domain
 }
 
 func (p *_Locale) SetDomain(domain string) Gettexter {
@@ -87,119 +126,228 @@ func (p *_Locale) SetDomain(domain string) Gettexter {
 	return p
 }
 
+func (p *_Locale) GetFallbacks() []string {
+	return p.view().GetFallbacks()
+}
+
+func (p *_Locale) SetFallbacks(langs ...string) Gettexter {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.fallbacks = append([]string(nil), langs...)
+	p.syncTrMap()
+	return p
+}
+
+func (p *_Locale) SetCacheSize(n int) Gettexter {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.cache.SetMaxSize(n)
+	p.resetViews()
+	return p
+}
+
+func (p *_Locale) SetUseFuzzy(useFuzzy bool) Gettexter {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if useFuzzy == p.useFuzzy {
+		return p
+	}
+
+	p.useFuzzy = useFuzzy
+	p.cache.Remove("", "")
+	p.resetViews()
+	p.syncTrMap()
+	return p
+}
+
+func (p *_Locale) Invalidate(domain, lang string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.cache.Remove(domain, lang)
+	p.resetViews()
+	p.syncTrMap()
+}
+
+// syncTrMap publishes the snapshot of the current domain and lang,
+// its catalogs are pinned in the cache.
 func (p *_Locale) syncTrMap() {
-	p.trMap = make(map[string]*translator)
-	trMapKey := p.makeTrMapKey(p.domain, p.lang)
+	p.cache.Pin(p.domain, fallbackLanguages(p.lang, p.fallbacks))
+	p.current.Store(p.newView(p.domain, p.lang, p.fallbacks))
+}
 
-	if tr, ok := p.trMap[trMapKey]; ok {
-		p.trCurrent = tr
-		return
+// loadTrChain returns the translators of the domain's languages,
+// they are loaded from the cache or from the FileSystem.
+func (p *_Locale) loadTrChain(domain string, langs []string) trChain {
+	var chain trChain
+	for _, lang := range langs {
+		chain = append(chain, p.loadTranslators(domain, lang)...)
 	}
+	return chain
+}
 
-	// try load po file
-	if data, err := p.fs.LoadMessagesFile(p.domain, p.lang, ".po"); err == nil {
-		if tr, err := newPoTranslator(fmt.Sprintf("%s_%s.po", p.domain, p.lang), data); err == nil {
-			p.trMap[trMapKey] = tr
-			p.trCurrent = tr
-			return
-		}
+// loadTranslators returns the translators of the domain and lang,
+// one for every layer of the FileSystem which has its catalog.
+func (p *_Locale) loadTranslators(domain, lang string) trChain {
+	if chain, ok := p.cache.Get(domain, lang); ok {
+		return chain
+	}
+	chain := p.newTranslators(domain, lang)
+	p.cache.Add(domain, lang, chain)
+	return chain
+}
+
+// newTranslators loads the translators of the domain and lang,
+// and records the first parse error, see LoadError.
+func (p *_Locale) newTranslators(domain, lang string) trChain {
+	key := trCacheKey{domain, lang}
+	delete(p.errors, key)
+
+	// try the loaded catalogs
+	if tr, ok := p.catalogs[p.makeTrMapKey(domain, lang)]; ok {
+		return trChain{tr}
 	}
 
-	// try load mo file
-	if data, err := p.fs.LoadMessagesFile(p.domain, p.lang, ".mo"); err == nil {
-		if tr, err := newMoTranslator(fmt.Sprintf("%s_%s.mo", p.domain, p.lang), data); err == nil {
-			p.trMap[trMapKey] = tr
-			p.trCurrent = tr
-			return
+	var chain trChain
+	for _, fs := range fsLayers(p.fs) {
+		tr, err := p.newTranslator(fs, domain, lang)
+		if err != nil && p.errors[key] == nil {
+			p.errors[key] = err
+		}
+		if tr != nilTranslator {
+			chain = append(chain, tr)
 		}
 	}
+	return chain
+}
 
-	// try load json file
-	if data, err := p.fs.LoadMessagesFile(p.domain, p.lang, ".json"); err == nil {
-		if tr, err := newJsonTranslator(p.lang, fmt.Sprintf("%s_%s.json", p.domain, p.lang), data); err == nil {
-			p.trMap[trMapKey] = tr
-			p.trCurrent = tr
-			return
+// newTranslator loads the po, mo or json file of the domain and lang.
+// The files which can't be parsed are skipped, the error of the first
+// one is returned.
+func (p *_Locale) newTranslator(fs FileSystem, domain, lang string) (tr *translator, err error) {
+	tr = nilTranslator
+	for _, ext := range []string{".po", ".mo", ".json"} {
+		data, e := fs.LoadMessagesFile(domain, lang, ext)
+		if e != nil {
+			continue
+		}
+		name := fmt.Sprintf("%s_%s%s", domain, lang, ext)
+		switch ext {
+		case ".po":
+			tr, e = newPoTranslator(name, data, p.useFuzzy)
+		case ".mo":
+			tr, e = newMoTranslator(name, data)
+		case ".json":
+			tr, e = newJsonTranslator(lang, name, data)
+		}
+		if e == nil {
+			return tr, err
+		}
+		if err == nil {
+			err = &LoadError{Domain: domain, Language: lang, File: name, FS: fs.String(), Err: e}
 		}
 	}
 
 	// no po/mo file
-	p.trMap[trMapKey] = nilTranslator
-	p.trCurrent = nilTranslator
-	return
+	return nilTranslator, err
 }
 
-func (p *_Locale) Gettext(msgid string) string {
+func (p *_Locale) LoadError(domain, lang string) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
-	return p.trCurrent.PGettext("", msgid)
+
+	if domain == "" {
+		domain = p.domain
+	}
+	if lang == "" {
+		lang = p.lang
+	}
+	if _, ok := p.cache.Peek(domain, lang); !ok {
+		p.loadTranslators(domain, lang)
+	}
+	return p.errors[trCacheKey{domain, lang}]
 }
 
-func (p *_Locale) PGettext(msgctxt, msgid string) string {
+func (p *_Locale) Diagnostics(domain, lang string) []po.Diagnostic {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
-	return p.trCurrent.PGettext(msgctxt, msgid)
+
+	if domain == "" {
+		domain = p.domain
+	}
+	if lang == "" {
+		lang = p.lang
+	}
+	var ds []po.Diagnostic
+	for _, tr := range p.loadTranslators(domain, lang) {
+		ds = append(ds, tr.Diagnostics...)
+	}
+	return ds
+}
+
+func (p *_Locale) Gettext(msgid string) string {
+	return p.view().Gettext(msgid)
+}
+
+func (p *_Locale) PGettext(msgctxt, msgid string) string {
+	return p.view().PGettext(msgctxt, msgid)
 }
 
 func (p *_Locale) NGettext(msgid, msgidPlural string, n int) string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	return p.trCurrent.PNGettext("", msgid, msgidPlural, n)
+	return p.view().NGettext(msgid, msgidPlural, n)
 }
 
 func (p *_Locale) PNGettext(msgctxt, msgid, msgidPlural string, n int) string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	return p.trCurrent.PNGettext(msgctxt, msgid, msgidPlural, n)
+	return p.view().PNGettext(msgctxt, msgid, msgidPlural, n)
+}
+
+func (p *_Locale) NGettext64(msgid, msgidPlural string, n int64) string {
+	return p.view().NGettext64(msgid, msgidPlural, n)
+}
+
+func (p *_Locale) PNGettext64(msgctxt, msgid, msgidPlural string, n int64) string {
+	return p.view().PNGettext64(msgctxt, msgid, msgidPlural, n)
+}
+
+func (p *_Locale) NGettextDecimal(msgid, msgidPlural string, n plural.Operands) string {
+	return p.view().NGettextDecimal(msgid, msgidPlural, n)
+}
+
+func (p *_Locale) PNGettextDecimal(msgctxt, msgid, msgidPlural string, n plural.Operands) string {
+	return p.view().PNGettextDecimal(msgctxt, msgid, msgidPlural, n)
+}
+
+func (p *_Locale) OGettext(msgid string, n int) string {
+	return p.view().OGettext(msgid, n)
+}
+
+func (p *_Locale) POGettext(msgctxt, msgid string, n int) string {
+	return p.view().POGettext(msgctxt, msgid, n)
 }
 
 func (p *_Locale) DGettext(domain, msgid string) string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	return p.gettext(domain, "", msgid, "", 0)
+	return p.view().DGettext(domain, msgid)
 }
 
 func (p *_Locale) DNGettext(domain, msgid, msgidPlural string, n int) string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	return p.gettext(domain, "", msgid, msgidPlural, n)
+	return p.view().DNGettext(domain, msgid, msgidPlural, n)
 }
 
 func (p *_Locale) DPGettext(domain, msgctxt, msgid string) string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	return p.gettext(domain, msgctxt, msgid, "", 0)
+	return p.view().DPGettext(domain, msgctxt, msgid)
 }
 
 func (p *_Locale) DPNGettext(domain, msgctxt, msgid, msgidPlural string, n int) string {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	return p.gettext(domain, msgctxt, msgid, msgidPlural, n)
+	return p.view().DPNGettext(domain, msgctxt, msgid, msgidPlural, n)
 }
 
 func (p *_Locale) Getdata(name string) []byte {
-	return p.getdata(p.domain, name)
+	return p.view().Getdata(name)
 }
 
 func (p *_Locale) DGetdata(domain, name string) []byte {
-	return p.getdata(domain, name)
-}
-
-func (p *_Locale) gettext(domain, msgctxt, msgid, msgidPlural string, n int) string {
-	if f, ok := p.trMap[p.makeTrMapKey(domain, p.lang)]; ok {
-		return f.PNGettext(msgctxt, msgid, msgidPlural, n)
-	}
-	return msgid
-}
-
-func (p *_Locale) getdata(domain, name string) []byte {
-	if data, err := p.fs.LoadResourceFile(domain, p.lang, name); err == nil {
-		return data
-	}
-	if p.lang != "default" {
-		if data, err := p.fs.LoadResourceFile(domain, "default", name); err == nil {
-			return data
-		}
-	}
-	return nil
+	return p.view().DGetdata(domain, name)
 }
@@ -5,7 +5,12 @@
 package gettext
 
 import (
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/chai2010/gettext-go/po"
 )
 
 func TestLocale(t *testing.T) {
@@ -28,3 +33,222 @@ func testLocal_zh_CN(t *testing.T, l Gettexter) {
 	got = l.PGettext("main.main", "Hello, world!")
 	tAssert(t, got == expect, got, expect)
 }
+
+func TestLocale_fallbacks(t *testing.T) {
+	newCatalog := func(lang string, msgs ...string) *Catalog {
+		f := &po.File{MimeHeader: po.Header{Language: lang}}
+		for i := 0; i+1 < len(msgs); i += 2 {
+			f.Messages = append(f.Messages, po.Message{MsgId: msgs[i], MsgStr: msgs[i+1]})
+		}
+		return NewPoCatalog(f)
+	}
+	l := NewWithCatalogs("test",
+		newCatalog("de_AT", "Hello", "Servus"),
+		newCatalog("de", "Hello", "Hallo", "Bye", "Tschüss"),
+		newCatalog("en", "Color", "Colour"),
+	).SetLanguage("de_AT")
+
+	tAssert(t, l.Gettext("Hello") == "Servus", l.Gettext("Hello"))
+	tAssert(t, l.Gettext("Bye") == "Tschüss", l.Gettext("Bye"))
+	tAssert(t, l.DGettext("test", "Bye") == "Tschüss", l.DGettext("test", "Bye"))
+	tAssert(t, l.Gettext("Color") == "Color", l.Gettext("Color"))
+
+	l.SetFallbacks("en")
+	tAssert(t, len(l.GetFallbacks()) == 1, l.GetFallbacks())
+	tAssert(t, l.Gettext("Color") == "Colour", l.Gettext("Color"))
+	tAssert(t, l.Gettext("Hello") == "Servus", l.Gettext("Hello"))
+	tAssert(t, l.NGettext("Apple", "Apples", 2) == "Apples")
+}
+
+func TestFallbackLanguages(t *testing.T) {
+	for _, v := range []struct {
+		lang      string
+		fallbacks []string
+		expect    string
+	}{
+		{"zh_TW", nil, "zh_TW zh default"},
+		{"de_AT", []string{"en"}, "de_AT de en default"},
+		{"zh-Hant-TW", []string{"zh_CN", "en_US"}, "zh-Hant-TW zh-Hant zh zh_CN en_US en default"},
+		{"default", []string{"en"}, "default en"},
+		{"sr_RS@latin", []string{"en"}, "sr_RS@latin sr@latin sr_RS sr en default"},
+		{"de_DE.utf8", nil, "de_DE.utf8 de_DE de.utf8 de default"},
+		{"../../etc", []string{"en", `..\x`, "a/b"}, "en default"},
+	} {
+		got := strings.Join(fallbackLanguages(v.lang, v.fallbacks), " ")
+		tAssert(t, got == v.expect, got, v.expect)
+	}
+}
+
+func TestLocale_invalidLanguage(t *testing.T) {
+	// "./examples/locale/default/LC_MESSAGES/../../zh_CN" is the zh_CN dir
+	l := newLocale("hello", "./examples/locale/default", nil)
+	for _, lang := range []string{"LC_MESSAGES/../../zh_CN", "../zh_CN"} {
+		got := l.WithLanguage(lang).Gettext("Hello, world!")
+		tAssert(t, got == "Hello, world!", lang, got)
+	}
+
+	fs := newOsFS("./examples/locale/default")
+	_, err := fs.LoadMessagesFile("hello", "../zh_CN", ".mo")
+	tAssert(t, err != nil)
+	_, err = fs.LoadMessagesFile("../../zh_CN/LC_MESSAGES/hello", "default", ".mo")
+	tAssert(t, err != nil)
+
+	fs = newOsFS("./examples/locale")
+	_, err = fs.LoadResourceFile("hello", "zh_CN", "poems.txt")
+	tAssert(t, err == nil, err)
+	_, err = fs.LoadResourceFile("hello", "zh_CN", "../../../default/LC_RESOURCE/hello/favicon.ico")
+	tAssert(t, err != nil)
+}
+
+func TestLocale_Diagnostics(t *testing.T) {
+	fs := mapFS{
+		"hello/zh_CN.po": strings.Replace(testTrPluralPoData, `msgstr[2] "many"`, "", 1),
+		"hello/zh_TW.po": testTrPluralPoData,
+	}
+	l := New("hello", "", fs).SetLanguage("zh_CN")
+	ds := l.Diagnostics("", "")
+	tAssert(t, len(ds) == 1 && ds[0].MsgId == "%d file", ds)
+	tAssert(t, l.NGettext("%d file", "%d files", 5) == "%d files")
+	tAssert(t, len(l.WithDomain("none").Diagnostics("hello", "")) == 1)
+	tAssert(t, len(l.WithLanguage("zh_TW").Diagnostics("", "")) == 0)
+	tAssert(t, l.LoadError("", "") == nil)
+}
+
+// mapFS is a FileSystem of the messages files "domain/lang.ext".
+type mapFS map[string]string
+
+func (p mapFS) LocaleList() []string { return nil }
+func (p mapFS) LoadMessagesFile(domain, lang, ext string) ([]byte, error) {
+	if s, ok := p[domain+"/"+lang+ext]; ok {
+		return []byte(s), nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+func (p mapFS) LoadResourceFile(domain, lang, name string) ([]byte, error) {
+	return nil, fmt.Errorf("not found")
+}
+func (p mapFS) String() string { return "mapfs" }
+
+func TestLocale_domains(t *testing.T) {
+	fs := mapFS{
+		"hello/de.po": `
+msgid "Hello"
+msgstr "Hallo"
+`,
+		"lib/de.po": `
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Open"
+msgstr "Öffnen"
+
+msgctxt "menu"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+`,
+		"lib/de_AT.po": `
+msgid "Open"
+msgstr "Aufmachen"
+`,
+	}
+	for _, l := range []Gettexter{
+		New("hello", "", fs).SetLanguage("de_AT"),
+		New("hello", "", fs).WithLanguage("de_AT"),
+	} {
+		tAssert(t, l.Gettext("Hello") == "Hallo", l.Gettext("Hello"))
+		tAssert(t, l.Gettext("Open") == "Open", l.Gettext("Open"))
+		tAssert(t, l.DGettext("lib", "Open") == "Aufmachen", l.DGettext("lib", "Open"))
+		tAssert(t, l.DPGettext("lib", "", "Open") == "Aufmachen")
+		tAssert(t, l.DNGettext("lib", "%d file", "%d files", 2) == "%d files")
+		tAssert(t, l.DPNGettext("lib", "menu", "%d file", "%d files", 2) == "%d Dateien")
+		tAssert(t, l.DGettext("none", "Open") == "Open")
+		tAssert(t, l.DGettext("hello", "Hello") == "Hallo")
+	}
+}
+
+// go test -run=^$ -bench=Gettext -cpu=1,8,64
+
+func BenchmarkLocale_Gettext(b *testing.B) {
+	l := New("hello", "./examples/locale").SetLanguage("zh_CN")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.PGettext("main.main", "Hello, world!")
+		}
+	})
+}
+
+func BenchmarkLocale_Gettext_mutex(b *testing.B) {
+	// the lookups under an exclusive lock, as _Locale did before the snapshots
+	var mu sync.Mutex
+	l := New("hello", "./examples/locale").SetLanguage("zh_CN")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mu.Lock()
+			l.PGettext("main.main", "Hello, world!")
+			mu.Unlock()
+		}
+	})
+}
+
+func BenchmarkLocale_Gettext_writer(b *testing.B) {
+	// the lookups with a concurrent writer switching the language
+	l := New("hello", "./examples/locale").SetLanguage("zh_CN")
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				l.SetLanguage("zh_TW")
+				l.SetLanguage("zh_CN")
+			}
+		}
+	}()
+	defer close(done)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.PGettext("main.main", "Hello, world!")
+		}
+	})
+}
+
+func TestLocale_LoadError(t *testing.T) {
+	fs := mapFS{
+		"hello/de.mo":   "bad mo",
+		"hello/de.po":   "msgid \"Hello\"\nmsgstr \"Hallo\"\n",
+		"hello/fr.mo":   "bad mo",
+		"hello/es.json": "[]",
+	}
+	l := New("hello", "", fs).SetLanguage("de")
+	tAssert(t, l.LoadError("", "") == nil, l.LoadError("", ""))
+	tAssert(t, l.Gettext("Hello") == "Hallo")
+	tAssert(t, l.LoadError("hello", "es") == nil, l.LoadError("hello", "es"))
+
+	err, ok := l.LoadError("hello", "fr").(*LoadError)
+	tAssert(t, ok, err)
+	tAssert(t, err.Domain == "hello" && err.Language == "fr" && err.File == "hello_fr.mo", err)
+	tAssert(t, err.Error() == "gettext: mapfs: load hello_fr.mo: gettext: invalid magic number", err.Error())
+	tAssert(t, l.WithLanguage("fr").LoadError("", "") == err)
+
+	// the error is dropped with the evicted catalog
+	l.SetCacheSize(1)
+	tAssert(t, len(l.(*_Locale).errors) == 0, l.(*_Locale).errors)
+
+	_, err2 := NewE("hello", "", fs)
+	tAssert(t, err2 == nil, err2)
+	_, err2 = NewE("hello", "./examples/none")
+	tAssert(t, err2 != nil)
+
+	DefaultLanguage, DefaultLanguages = "fr", []string{"fr"}
+	defer func() {
+		DefaultLanguage, DefaultLanguages = getDefaultLanguage(), getDefaultLanguages()
+	}()
+	_, err2 = NewE("hello", "", fs)
+	tAssert(t, err2 != nil && err2.(*LoadError).File == "hello_fr.mo", err2)
+}
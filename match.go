@@ -0,0 +1,184 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseAcceptLanguage parses an HTTP Accept-Language header, such as
+// "zh-Hant-TW, zh;q=0.8, en;q=0.5". It returns the language tags sorted
+// by their quality values, without the "*" and the q=0 ones.
+func ParseAcceptLanguage(header string) []string {
+	type tagQ struct {
+		tag string
+		q   float64
+	}
+	var tags []tagQ
+	for _, s := range strings.Split(header, ",") {
+		var (
+			fields = strings.Split(s, ";")
+			tag    = strings.TrimSpace(fields[0])
+			q      = 1.0
+		)
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if x, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = x
+				}
+			}
+		}
+		if tag == "" || tag == "*" || q <= 0 {
+			continue
+		}
+		tags = append(tags, tagQ{tag, q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	var result []string
+	for _, v := range tags {
+		result = append(result, v.tag)
+	}
+	return result
+}
+
+// MatchLanguage returns the best locale of the available ones (such as
+// "zh_TW", "sr@latin") for the preferred BCP 47 tags or locale names,
+// in the order of preference.
+//
+// The locales of the first tag which matches any locale win. A locale
+// with the same language and region is preferred, then a locale without
+// region ("de" for "de-AT"), then another region of the language. The
+// script of the tag must match: "zh-Hant" matches "zh_TW" and "zh_HK",
+// but not "zh_CN"; "sr-Latn" matches "sr@latin".
+//
+// The "default" locale is never matched, ok is false if nothing matches.
+func MatchLanguage(locales []string, tags ...string) (lang string, ok bool) {
+	for _, tag := range tags {
+		t := parseLangTag(tag)
+		if t.lang == "" {
+			continue
+		}
+		best, bestScore := "", 0
+		for _, locale := range locales {
+			if locale == "default" {
+				continue
+			}
+			if score := t.match(parseLangTag(locale)); score > bestScore {
+				best, bestScore = locale, score
+			}
+		}
+		if best != "" {
+			return best, true
+		}
+	}
+	return "", false
+}
+
+// MatchAcceptLanguage returns the best locale of the file system's
+// LocaleList for an HTTP Accept-Language header, see MatchLanguage.
+//
+// Examples:
+//
+//	g := New("hello", "locale")
+//	if lang, ok := MatchAcceptLanguage(g.FileSystem(), r.Header.Get("Accept-Language")); ok {
+//		g.SetLanguage(lang)
+//	}
+func MatchAcceptLanguage(fs FileSystem, header string) (lang string, ok bool) {
+	return MatchLanguage(fs.LocaleList(), ParseAcceptLanguage(header)...)
+}
+
+// langTag is the parsed BCP 47 tag or locale name, lower case.
+type langTag struct {
+	lang   string // "zh"
+	script string // "hant", inferred from the region if missing
+	region string // "tw"
+}
+
+// modifierScripts maps the locale modifiers to the BCP 47 scripts.
+var modifierScripts = map[string]string{
+	"latin":    "latn",
+	"cyrillic": "cyrl",
+}
+
+// parseLangTag parses "zh-Hant-TW", "zh_TW.UTF-8" or "sr_RS@latin".
+func parseLangTag(s string) langTag {
+	var t langTag
+
+	s = strings.ToLower(strings.TrimSpace(s))
+	if idx := strings.Index(s, "@"); idx != -1 {
+		t.script = modifierScripts[s[idx+1:]]
+		s = s[:idx]
+	}
+	if idx := strings.Index(s, "."); idx != -1 {
+		s = s[:idx]
+	}
+
+	for i, sub := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' }) {
+		if i == 0 {
+			t.lang = sub
+			continue
+		}
+		if len(sub) == 1 {
+			break // extensions, such as "-u-nu-hans"
+		}
+		if len(sub) == 4 && t.region == "" {
+			t.script = sub
+		} else if (len(sub) == 2 || len(sub) == 3 && isDigits(sub)) && t.region == "" {
+			t.region = sub
+		}
+	}
+	if t.script == "" {
+		t.script = defaultScript(t.lang, t.region)
+	}
+	return t
+}
+
+// defaultScript returns the script of the languages written
+// in more than one script, or "".
+func defaultScript(lang, region string) string {
+	switch lang {
+	case "zh":
+		switch region {
+		case "tw", "hk", "mo":
+			return "hant"
+		}
+		return "hans"
+	case "sr":
+		return "cyrl"
+	}
+	return ""
+}
+
+// match returns how well the locale l matches the tag t, 0 if it doesn't.
+func (t langTag) match(l langTag) int {
+	if t.lang != l.lang || t.script != l.script {
+		return 0
+	}
+	switch {
+	case t.region == l.region:
+		return 4
+	case l.region == "":
+		return 3
+	case t.region == "" && l.region == l.lang:
+		return 2 // "fr" => "fr_FR"
+	default:
+		return 1
+	}
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
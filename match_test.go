@@ -0,0 +1,66 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	for _, v := range []struct {
+		header string
+		expect string
+	}{
+		{"", ""},
+		{"en", "en"},
+		{"zh-Hant-TW, zh;q=0.8, en;q=0.5", "zh-Hant-TW zh en"},
+		{"en;q=0.5, de-AT, de;q=0.9, *;q=0.1", "de-AT de en"},
+		{"fr;q=0, it , es;q=0.7", "it es"},
+	} {
+		got := strings.Join(ParseAcceptLanguage(v.header), " ")
+		tAssert(t, got == v.expect, v.header, got, v.expect)
+	}
+}
+
+func TestMatchLanguage(t *testing.T) {
+	locales := []string{"default", "de", "en_US", "fr_CA", "fr_FR", "pt_BR", "sr", "sr@latin", "zh_CN", "zh_TW"}
+	for _, v := range []struct {
+		tags   []string
+		expect string
+	}{
+		{[]string{"zh-Hant-TW"}, "zh_TW"},
+		{[]string{"zh-Hant-HK"}, "zh_TW"},
+		{[]string{"zh-Hant"}, "zh_TW"},
+		{[]string{"zh-Hans"}, "zh_CN"},
+		{[]string{"zh-SG"}, "zh_CN"},
+		{[]string{"zh_TW.UTF-8"}, "zh_TW"},
+		{[]string{"de-AT"}, "de"},
+		{[]string{"fr"}, "fr_FR"},
+		{[]string{"fr-BE"}, "fr_CA"},
+		{[]string{"en-GB"}, "en_US"},
+		{[]string{"pt-PT"}, "pt_BR"},
+		{[]string{"sr-Latn-RS"}, "sr@latin"},
+		{[]string{"sr-RS"}, "sr"},
+		{[]string{"ja", "it", "de-CH"}, "de"},
+		{[]string{"en-US-u-ca-gregory"}, "en_US"},
+		{[]string{"ja"}, ""},
+		{[]string{"default"}, ""},
+		{nil, ""},
+	} {
+		got, ok := MatchLanguage(locales, v.tags...)
+		tAssert(t, got == v.expect && ok == (v.expect != ""), v.tags, got, v.expect)
+	}
+}
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	fs := OS("./examples/locale")
+	lang, ok := MatchAcceptLanguage(fs, "zh-Hant-TW, zh;q=0.8, en;q=0.5")
+	tAssert(t, ok && lang == "zh_TW", lang)
+	lang, ok = MatchAcceptLanguage(fs, "zh-Hans")
+	tAssert(t, ok && lang == "zh_CN", lang)
+	_, ok = MatchAcceptLanguage(fs, "ja, en")
+	tAssert(t, !ok)
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/chai2010/gettext-go/plural"
+	"github.com/chai2010/gettext-go/po"
+)
+
+// Missing is a message which isn't translated in the language,
+// see Gettexter.SetMissingHandler.
+type Missing struct {
+	Domain      string
+	Language    string
+	MsgContext  string
+	MsgId       string
+	MsgIdPlural string
+	Ordinal     bool // a message of OGettext or POGettext, see po.NewOrdinalMessage
+}
+
+// missingHandler is the missing handler of a _Locale or a _View,
+// with the messages already reported. A nil fn disables it.
+type missingHandler struct {
+	fn   func(m Missing)
+	seen sync.Map // Missing => bool
+}
+
+func (p *_Locale) SetMissingHandler(fn func(m Missing)) Gettexter {
+	if fn == nil {
+		p.missing.Store((*missingHandler)(nil))
+	} else {
+		p.missing.Store(&missingHandler{fn: fn})
+	}
+	return p
+}
+
+// reportMissing calls the view's missing handler (or the _Locale's,
+// if the view has none), if the message wasn't reported before.
+func (p *_View) reportMissing(m Missing) {
+	h := p.missing
+	if h == nil {
+		h, _ = p.locale.missing.Load().(*missingHandler)
+	}
+	if h == nil || h.fn == nil {
+		return
+	}
+	if _, seen := h.seen.LoadOrStore(m, true); !seen {
+		h.fn(m)
+	}
+}
+
+// MissingCollector collects the missing messages, it's safe
+// for concurrent use.
+//
+// Examples:
+//
+//	c := new(MissingCollector)
+//	g := New("hello", "locale").SetLanguage("zh_CN").SetMissingHandler(c.Add)
+//	// run the app ...
+//	c.PoFile("hello", "zh_CN").Save("hello_zh_CN.po")
+type MissingCollector struct {
+	mutex sync.Mutex
+	msgs  []Missing
+	seen  map[Missing]bool
+}
+
+// Add adds the missing message, the duplicates are ignored.
+func (c *MissingCollector) Add(m Missing) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.seen == nil {
+		c.seen = make(map[Missing]bool)
+	}
+	if c.seen[m] {
+		return
+	}
+	c.seen[m] = true
+	c.msgs = append(c.msgs, m)
+}
+
+// Missing returns the missing messages, in the order they were added.
+func (c *MissingCollector) Missing() []Missing {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]Missing(nil), c.msgs...)
+}
+
+// PoFile returns the missing messages of the domain and lang as an
+// untranslated po file, sorted by msgctxt and msgid.
+//
+// The Plural-Forms header is the standard one of the lang (see
+// plural.TableForms), the plural messages have its nplurals msgstr
+// and the ordinal messages have a msgstr for each CLDR ordinal category.
+func (c *MissingCollector) PoFile(domain, lang string) *po.File {
+	f := &po.File{
+		MimeHeader: po.Header{
+			Language:                lang,
+			MimeVersion:             "1.0",
+			ContentType:             "text/plain; charset=UTF-8",
+			ContentTransferEncoding: "8bit",
+			PluralForms:             plural.TableForms(lang),
+		},
+	}
+	nplurals := 2
+	if forms, err := plural.ParseForms(f.MimeHeader.PluralForms); err == nil {
+		nplurals = forms.NPlurals
+	}
+	for _, m := range c.Missing() {
+		if m.Domain != domain || m.Language != lang {
+			continue
+		}
+		if m.Ordinal {
+			n := len(plural.Ordinal(lang).Categories)
+			f.Messages = append(f.Messages, po.NewOrdinalMessage(m.MsgContext, m.MsgId, make([]string, n)))
+			continue
+		}
+		msg := po.Message{
+			MsgContext:  m.MsgContext,
+			MsgId:       m.MsgId,
+			MsgIdPlural: m.MsgIdPlural,
+		}
+		if m.MsgIdPlural != "" {
+			msg.MsgStrPlural = make([]string, nplurals)
+		}
+		f.Messages = append(f.Messages, msg)
+	}
+	sort.SliceStable(f.Messages, func(i, j int) bool {
+		a, b := &f.Messages[i], &f.Messages[j]
+		if a.MsgContext != b.MsgContext {
+			return a.MsgContext < b.MsgContext
+		}
+		return a.MsgId < b.MsgId
+	})
+	return f
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chai2010/gettext-go/plural"
+	"github.com/chai2010/gettext-go/po"
+)
+
+func TestLocale_missing(t *testing.T) {
+	fs := mapFS{
+		"hello/de.po": `
+msgid "Hello"
+msgstr "Hallo"
+`,
+	}
+	var (
+		c      = new(MissingCollector)
+		called int
+	)
+	g := New("hello", "", fs).SetLanguage("de").SetMissingHandler(func(m Missing) {
+		called++
+		c.Add(m)
+	})
+
+	tAssert(t, g.Gettext("Hello") == "Hallo")
+	tAssert(t, called == 0, called)
+
+	tAssert(t, g.Gettext("Open") == "Open")
+	tAssert(t, g.Gettext("Open") == "Open")
+	tAssert(t, g.PNGettext("menu", "%d file", "%d files", 2) == "%d files")
+	tAssert(t, g.DGettext("lib", "Close") == "Close")
+	tAssert(t, g.WithLanguage("fr").Gettext("Hello") == "Hello")
+	tAssert(t, called == 4, called)
+
+	tAssert(t, len(c.Missing()) == 4, c.Missing())
+	tAssert(t, c.Missing()[3] == Missing{Domain: "hello", Language: "fr", MsgId: "Hello"}, c.Missing()[3])
+
+	f := c.PoFile("hello", "de")
+	tAssert(t, f.MimeHeader.Language == "de")
+	tAssert(t, len(f.Messages) == 2, f.Messages)
+	tAssert(t, f.Messages[0].MsgId == "Open", f.Messages[0])
+	tAssert(t, f.Messages[1].MsgContext == "menu", f.Messages[1])
+	tAssert(t, strings.Contains(f.String(), `msgid_plural "%d files"`), f.String())
+
+	g.SetMissingHandler(nil)
+	g.Gettext("Save")
+	tAssert(t, called == 4, called)
+}
+
+func TestMissingCollector_PoFile(t *testing.T) {
+	c := new(MissingCollector)
+	g := New("hello", "", mapFS{}).SetLanguage("ru").SetMissingHandler(c.Add)
+	g.NGettext("%d file", "%d files", 5)
+	g.OGettext("%d place", 2)
+	g.POGettext("race", "%d place", 2)
+
+	f := c.PoFile("hello", "ru")
+	tAssert(t, f.MimeHeader.PluralForms == plural.TableForms("ru"), f.MimeHeader.PluralForms)
+	tAssert(t, len(f.Messages) == 3, f.Messages)
+	tAssert(t, len(f.Messages[0].MsgStrPlural) == 3, f.Messages[0])
+	tAssert(t, f.Messages[1].MsgContext == po.OrdinalContext && f.Messages[1].IsOrdinal(), f.Messages[1])
+	tAssert(t, f.Messages[2].MsgContext == po.OrdinalMsgContext("race"), f.Messages[2])
+	tAssert(t, len(f.Messages[1].MsgStrPlural) == len(plural.Ordinal("ru").Categories), f.Messages[1])
+
+	// translated
+	f.Messages[0].MsgStrPlural = []string{"%d файл", "%d файла", "%d файлов"}
+	f.Messages[1].MsgStrPlural = []string{"%d-е место"}
+	g = New("hello", "", mapFS{"hello/ru.po": f.String()}).SetLanguage("ru")
+	tAssert(t, g.NGettext("%d file", "%d files", 5) == "%d файлов", g.NGettext("%d file", "%d files", 5))
+	tAssert(t, g.OGettext("%d place", 2) == "%d-е место", g.OGettext("%d place", 2))
+
+	f, err := po.Load([]byte(f.String()))
+	tAssert(t, err == nil, err)
+	tAssert(t, len(NewPoCatalog(f).Diagnostics()) == 0, NewPoCatalog(f).Diagnostics())
+}
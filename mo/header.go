@@ -77,33 +77,48 @@ func (p *Header) fromMessage(msg *Message) {
 }
 
 func (p *Header) toMessage() Message {
+	var buf bytes.Buffer
+	for _, kv := range p.fields() {
+		fmt.Fprintf(&buf, "%s: %s\n", kv[0], kv[1])
+	}
 	return Message{
-		MsgStr: p.String(),
+		MsgStr: buf.String(),
 	}
 }
 
+// fields returns the header fields in the order they are written.
+func (p *Header) fields() [][2]string {
+	var ss [][2]string
+	add := func(key, val string, always bool) {
+		if always || val != "" {
+			ss = append(ss, [2]string{key, val})
+		}
+	}
+	add("Project-Id-Version", p.ProjectIdVersion, true)
+	add("Report-Msgid-Bugs-To", p.ReportMsgidBugsTo, true)
+	add("POT-Creation-Date", p.POTCreationDate, true)
+	add("PO-Revision-Date", p.PORevisionDate, true)
+	add("Last-Translator", p.LastTranslator, true)
+	add("Language-Team", p.LanguageTeam, true)
+	add("Language", p.Language, true)
+	add("MIME-Version", p.MimeVersion, false)
+	add("Content-Type", p.ContentType, true)
+	add("Content-Transfer-Encoding", p.ContentTransferEncoding, true)
+	add("Plural-Forms", p.PluralForms, false)
+	add("X-Generator", p.XGenerator, false)
+	for k, v := range p.UnknowFields {
+		add(k, v, true)
+	}
+	return ss
+}
+
 // String returns the po format header string.
 func (p Header) String() string {
 	var buf bytes.Buffer
 	fmt.Fprintf(&buf, `msgid ""`+"\n")
 	fmt.Fprintf(&buf, `msgstr ""`+"\n")
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "Project-Id-Version", p.ProjectIdVersion)
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "Report-Msgid-Bugs-To", p.ReportMsgidBugsTo)
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "POT-Creation-Date", p.POTCreationDate)
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "PO-Revision-Date", p.PORevisionDate)
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "Last-Translator", p.LastTranslator)
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "Language-Team", p.LanguageTeam)
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "Language", p.Language)
-	if p.MimeVersion != "" {
-		fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "MIME-Version", p.MimeVersion)
-	}
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "Content-Type", p.ContentType)
-	fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "Content-Transfer-Encoding", p.ContentTransferEncoding)
-	if p.XGenerator != "" {
-		fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", "X-Generator", p.XGenerator)
-	}
-	for k, v := range p.UnknowFields {
-		fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", k, v)
+	for _, kv := range p.fields() {
+		fmt.Fprintf(&buf, `"%s: %s\n"`+"\n", kv[0], kv[1])
 	}
 	return buf.String()
 }
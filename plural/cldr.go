@@ -0,0 +1,293 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Category is a CLDR plural category.
+//
+// See http://unicode.org/reports/tr35/tr35-numbers.html#Language_Plural_Rules
+type Category int
+
+const (
+	Zero Category = iota
+	One
+	Two
+	Few
+	Many
+	Other
+)
+
+var categoryNames = [...]string{"zero", "one", "two", "few", "many", "other"}
+
+// String returns the CLDR name of the category, such as "few".
+func (c Category) String() string {
+	if c >= Zero && c <= Other {
+		return categoryNames[c]
+	}
+	return "Category(" + strconv.Itoa(int(c)) + ")"
+}
+
+// ParseCategory parses a CLDR category name, such as "few".
+func ParseCategory(name string) (Category, error) {
+	for i, s := range categoryNames {
+		if s == name {
+			return Category(i), nil
+		}
+	}
+	return Other, fmt.Errorf("plural: unknown category %q", name)
+}
+
+// Operands are the CLDR plural operands of a decimal number.
+//
+// For "1.50": N = 1.5, I = 1, V = 2, W = 1, F = 50, T = 5.
+type Operands struct {
+	N float64 // absolute value of the source number
+	I uint64  // integer digits of n
+	V int     // number of visible fraction digits in n, with trailing zeros
+	W int     // number of visible fraction digits in n, without trailing zeros
+	F uint64  // visible fraction digits in n, with trailing zeros
+	T uint64  // visible fraction digits in n, without trailing zeros
+	E int     // exponent of the compact decimal notation ("1.2c6", "1.2e6")
+}
+
+// IntOperands returns the operands of an integer.
+func IntOperands(n int64) Operands {
+	i := uint64(n)
+	if n < 0 {
+		i = -i
+	}
+	return Operands{N: float64(i), I: i}
+}
+
+// FloatOperands returns the operands of a float formatted with
+// prec fraction digits, as strconv.FormatFloat(f, 'f', prec, 64) does.
+//
+// A negative prec uses the smallest number of digits necessary.
+func FloatOperands(f float64, prec int) (Operands, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return Operands{}, fmt.Errorf("plural: invalid number %v", f)
+	}
+	return ParseOperands(strconv.FormatFloat(f, 'f', prec, 64))
+}
+
+// ParseOperands parses the operands of a decimal number, such as "1.50".
+//
+// The visible fraction digits are kept, so "1" and "1.0" may have
+// different plural categories. The CLDR compact decimal notation
+// ("1.2c6" or "1.2e6") is accepted too. The digits which don't fit an
+// uint64 are reduced like Uint64 does (see parseDigits), and the
+// operands are zero if an error is returned.
+func ParseOperands(s string) (Operands, error) {
+	var ops Operands
+	src := s
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "-")
+	if idx := strings.IndexAny(s, "ce"); idx != -1 {
+		e, err := strconv.Atoi(s[idx+1:])
+		if err != nil || e < 0 {
+			return Operands{}, fmt.Errorf("plural: invalid number %q", src)
+		}
+		s, ops.E = s[:idx], e
+	}
+
+	intPart, fracPart := s, ""
+	if idx := strings.Index(s, "."); idx != -1 {
+		intPart, fracPart = s[:idx], s[idx+1:]
+	}
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Operands{}, fmt.Errorf("plural: invalid number %q", src)
+	}
+
+	// move the decimal point of the compact notation
+	for e := ops.E; e > 0; e-- {
+		if fracPart != "" {
+			intPart, fracPart = intPart+fracPart[:1], fracPart[1:]
+		} else {
+			intPart += "0"
+		}
+	}
+
+	ops.I = parseDigits(intPart)
+	if fracPart != "" {
+		trimmed := strings.TrimRight(fracPart, "0")
+		ops.V, ops.W = len(fracPart), len(trimmed)
+		ops.F = parseDigits(fracPart)
+		if trimmed != "" {
+			ops.T = parseDigits(trimmed)
+		}
+	}
+	ops.N, _ = strconv.ParseFloat(intPart+"."+fracPart+"0", 64)
+	return ops, nil
+}
+
+// parseDigits parses the decimal digits of an operand. The numbers which
+// don't fit an uint64 are replaced by n%1000000+1000000, as Uint64 does,
+// so the last digits and the magnitude are kept.
+func parseDigits(s string) uint64 {
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return n
+	}
+	n, _ := strconv.ParseUint(s[len(s)-6:], 10, 64)
+	return n + 1000000
+}
+
+// value returns the operand's value, ok is false if it's not an integer.
+func (p *Operands) value(operand byte) (x uint64, ok bool) {
+	switch operand {
+	case 'n':
+		return p.I, p.T == 0
+	case 'i':
+		return p.I, true
+	case 'v':
+		return uint64(p.V), true
+	case 'w':
+		return uint64(p.W), true
+	case 'f':
+		return p.F, true
+	case 't':
+		return p.T, true
+	case 'e', 'c':
+		return uint64(p.E), true
+	}
+	return 0, false
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rules are the CLDR plural rules of a locale.
+type Rules struct {
+	Locale     string     // the CLDR locale of the rules, such as "pt_PT"
+	Categories []Category // the used categories, in order, "other" is the last one
+	conds      []cldrCondition
+}
+
+// Cardinal returns the CLDR cardinal plural rules of the language,
+// such as "en", "pt_BR", "pt-PT" or "sr_RS@latin".
+//
+// If the language is unknown, the rules of the "root" locale
+// (everything is "other") are returned.
+func Cardinal(lang string) *Rules {
+	cldrOnce.Do(initCldrRules)
+	return lookupRules(cldrCardinal, lang)
+}
+
+// Ordinal returns the CLDR ordinal plural rules of the language,
+// used to select the forms of "1st", "2nd", "3rd", ...
+//
+// If the language is unknown, the rules of the "root" locale
+// (everything is "other") are returned.
+func Ordinal(lang string) *Rules {
+	cldrOnce.Do(initCldrRules)
+	return lookupRules(cldrOrdinal, lang)
+}
+
+// Category returns the plural category of the operands.
+func (p *Rules) Category(ops Operands) Category {
+	for i, cond := range p.conds {
+		if cond.match(&ops) {
+			return p.Categories[i]
+		}
+	}
+	return Other
+}
+
+// IntCategory returns the plural category of an integer.
+func (p *Rules) IntCategory(n int64) Category {
+	return p.Category(IntOperands(n))
+}
+
+// Index maps a category to the gettext msgstr index.
+//
+// The gettext plural forms are the used categories in the CLDR order
+// (zero, one, two, few, many, other). The index of a category that is not
+// used by the locale is the index of "other". A catalog with fewer plural
+// forms than categories should clamp the index, as NGettext does.
+//
+// The forms of a gettext catalog are in the order of its Plural-Forms
+// formula, which may differ (ru has no "other" form), see DecimalFormula.
+func (p *Rules) Index(c Category) int {
+	for i, v := range p.Categories {
+		if v == c {
+			return i
+		}
+	}
+	return len(p.Categories) - 1
+}
+
+// Formula returns a gettext style formula of the integer rules.
+func (p *Rules) Formula() func(n int) int {
+	return func(n int) int {
+		return p.Index(p.IntCategory(int64(n)))
+	}
+}
+
+var (
+	cldrOnce     sync.Once
+	cldrCardinal map[string]*Rules
+	cldrOrdinal  map[string]*Rules
+)
+
+func initCldrRules() {
+	cldrCardinal = makeRulesMap(cldrCardinalTable)
+	cldrOrdinal = makeRulesMap(cldrOrdinalTable)
+}
+
+func makeRulesMap(table []cldrTableEntry) map[string]*Rules {
+	m := make(map[string]*Rules)
+	for _, v := range table {
+		var (
+			cats  []Category
+			conds []cldrCondition
+		)
+		for _, s := range v.Rules {
+			idx := strings.Index(s, ":")
+			if idx < 0 {
+				panic(fmt.Sprintf("plural: invalid CLDR rule %q", s))
+			}
+			c, err := ParseCategory(strings.TrimSpace(s[:idx]))
+			if err != nil {
+				panic(err)
+			}
+			cond, err := parseCldrRule(s[idx+1:])
+			if err != nil {
+				panic(err)
+			}
+			cats, conds = append(cats, c), append(conds, cond)
+		}
+		cats, conds = append(cats, Other), append(conds, cldrCondition{{}})
+
+		for _, locale := range strings.Fields(v.Locales) {
+			m[strings.ToLower(locale)] = &Rules{
+				Locale:     locale,
+				Categories: cats,
+				conds:      conds,
+			}
+		}
+	}
+	return m
+}
+
+func lookupRules(m map[string]*Rules, lang string) *Rules {
+	for _, key := range localeKeys(lang) {
+		if r, ok := m[key]; ok {
+			return r
+		}
+	}
+	return m["root"]
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The CLDR plural rule syntax (see UTS #35, Language Plural Rules):
+//
+//	condition     = and_condition ("or" and_condition)*
+//	and_condition = relation ("and" relation)*
+//	relation      = expr ("=" | "!=") range_list
+//	expr          = operand ("%" value)?
+//	operand       = "n" | "i" | "f" | "t" | "v" | "w" | "e" | "c"
+//	range_list    = (range | value) ("," range_list)*
+//	range         = value ".." value
+//
+// The samples ("@integer ...", "@decimal ...") are ignored.
+
+// cldrCondition is a parsed CLDR plural rule: or-ed and-conditions.
+type cldrCondition [][]cldrRelation
+
+type cldrRelation struct {
+	operand byte
+	mod     uint64 // 0 means no modulus
+	not     bool
+	ranges  [][2]uint64
+}
+
+func (p cldrCondition) match(ops *Operands) bool {
+	for _, and := range p {
+		ok := true
+		for _, r := range and {
+			if !r.match(ops) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *cldrRelation) match(ops *Operands) bool {
+	// a fractional n (and so n % m) is never in a range
+	x, ok := ops.value(p.operand)
+	if p.mod != 0 {
+		x %= p.mod
+	}
+	in := false
+	if ok {
+		for _, r := range p.ranges {
+			if r[0] <= x && x <= r[1] {
+				in = true
+				break
+			}
+		}
+	}
+	return in != p.not
+}
+
+// parseCldrRule parses a CLDR plural rule, such as
+// "v = 0 and i % 10 = 1 and i % 100 != 11".
+func parseCldrRule(rule string) (cldrCondition, error) {
+	if idx := strings.Index(rule, "@"); idx != -1 {
+		rule = rule[:idx]
+	}
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		// the "other" rule matches everything
+		return cldrCondition{{}}, nil
+	}
+
+	var cond cldrCondition
+	for _, s := range strings.Split(rule, " or ") {
+		var and []cldrRelation
+		for _, s := range strings.Split(s, " and ") {
+			r, err := parseCldrRelation(strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("plural: %q: %v", rule, err)
+			}
+			and = append(and, r)
+		}
+		cond = append(cond, and)
+	}
+	return cond, nil
+}
+
+func parseCldrRelation(s string) (r cldrRelation, err error) {
+	var lhs, rhs string
+	if idx := strings.Index(s, "!="); idx != -1 {
+		lhs, rhs, r.not = s[:idx], s[idx+2:], true
+	} else if idx := strings.Index(s, "="); idx != -1 {
+		lhs, rhs = s[:idx], s[idx+1:]
+	} else {
+		return r, fmt.Errorf("invalid relation %q", s)
+	}
+
+	lhs = strings.TrimSpace(lhs)
+	if idx := strings.Index(lhs, "%"); idx != -1 {
+		if r.mod, err = parseCldrValue(lhs[idx+1:]); err != nil || r.mod == 0 {
+			return r, fmt.Errorf("invalid modulus %q", s)
+		}
+		lhs = strings.TrimSpace(lhs[:idx])
+	}
+	if len(lhs) != 1 || !strings.Contains("nifvtwec", lhs) {
+		return r, fmt.Errorf("invalid operand %q", lhs)
+	}
+	r.operand = lhs[0]
+
+	for _, s := range strings.Split(rhs, ",") {
+		var lo, hi uint64
+		if idx := strings.Index(s, ".."); idx != -1 {
+			if lo, err = parseCldrValue(s[:idx]); err != nil {
+				return
+			}
+			if hi, err = parseCldrValue(s[idx+2:]); err != nil {
+				return
+			}
+		} else {
+			if lo, err = parseCldrValue(s); err != nil {
+				return
+			}
+			hi = lo
+		}
+		r.ranges = append(r.ranges, [2]uint64{lo, hi})
+	}
+	return r, nil
+}
+
+func parseCldrValue(s string) (uint64, error) {
+	v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
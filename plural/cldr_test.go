@@ -0,0 +1,204 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseOperands(t *testing.T) {
+	for _, v := range []struct {
+		in  string
+		out Operands
+	}{
+		{"1", Operands{N: 1, I: 1}},
+		{"-1", Operands{N: 1, I: 1}},
+		{"1.0", Operands{N: 1, I: 1, V: 1}},
+		{"1.50", Operands{N: 1.5, I: 1, V: 2, W: 1, F: 50, T: 5}},
+		{"0.03", Operands{N: 0.03, V: 2, W: 2, F: 3, T: 3}},
+		{"1.2c6", Operands{N: 1200000, I: 1200000, E: 6}},
+		{"1.2345e3", Operands{N: 1234.5, I: 1234, V: 1, W: 1, F: 5, T: 5, E: 3}},
+
+		// the digits which don't fit an uint64 are reduced, see Uint64
+		{"100000000000000000000", Operands{N: 1e20, I: 1000000}},
+		{"123456789012345678901.5", Operands{N: 123456789012345678901.5, I: 1678901, V: 1, W: 1, F: 5, T: 5}},
+		{"1.2c20", Operands{N: 1.2e20, I: 1000000, E: 20}},
+		{"0.123456789012345678901", Operands{N: 0.123456789012345678901, V: 21, W: 21, F: 1678901, T: 1678901}},
+	} {
+		ops, err := ParseOperands(v.in)
+		if err != nil {
+			t.Fatalf("%s: %v", v.in, err)
+		}
+		if ops != v.out {
+			t.Fatalf("%s: expect = %+v, got = %+v", v.in, v.out, ops)
+		}
+	}
+
+	for _, s := range []string{"", ".5", "1..2", "x", "1e", "1.5.5", "1.5e-1", "12x"} {
+		if ops, err := ParseOperands(s); err == nil || ops != (Operands{}) {
+			t.Fatalf("%q: expect error and zero operands, got = %+v, %v", s, ops, err)
+		}
+	}
+}
+
+func TestFloatOperands(t *testing.T) {
+	for _, v := range []struct {
+		f    float64
+		prec int
+		out  Operands
+	}{
+		{1.5, -1, Operands{N: 1.5, I: 1, V: 1, W: 1, F: 5, T: 5}},
+		{1, 2, Operands{N: 1, I: 1, V: 2}},
+		{1e20, -1, Operands{N: 1e20, I: 1000000}},
+		{-1e20, 0, Operands{N: 1e20, I: 1000000}},
+	} {
+		ops, err := FloatOperands(v.f, v.prec)
+		if err != nil {
+			t.Fatalf("%v: %v", v.f, err)
+		}
+		if ops != v.out {
+			t.Fatalf("%v: expect = %+v, got = %+v", v.f, v.out, ops)
+		}
+	}
+
+	for _, f := range []float64{math.Inf(1), math.NaN()} {
+		if ops, err := FloatOperands(f, -1); err == nil || ops != (Operands{}) {
+			t.Fatalf("%v: expect error and zero operands, got = %+v, %v", f, ops, err)
+		}
+	}
+}
+
+func TestCardinal(t *testing.T) {
+	for _, v := range []struct {
+		lang string
+		num  string
+		cat  Category
+	}{
+		{"zh_CN", "1", Other},
+		{"xx", "1", Other},
+		{"en", "1", One},
+		{"en_US.UTF-8", "1", One},
+		{"en", "1.0", Other},
+		{"en", "0", Other},
+		{"en", "1.5", Other},
+		{"fr", "0", One},
+		{"fr", "1.5", One},
+		{"fr", "2", Other},
+		{"fr", "1000000", Many},
+		{"fr", "1c6", Many},
+		{"pt", "0", One},
+		{"pt-PT", "0", Other},
+		{"pt_PT", "1", One},
+		{"ru", "1", One},
+		{"ru", "21", One},
+		{"ru", "11", Many},
+		{"ru", "22", Few},
+		{"ru", "5", Many},
+		{"ru", "1.5", Other},
+		{"pl", "22", Few},
+		{"pl", "12", Many},
+		{"pl", "0.5", Other},
+		{"cs", "3", Few},
+		{"cs", "1.5", Many},
+		{"lt", "1.5", Many},
+		{"lv", "0", Zero},
+		{"lv", "0.1", One},
+		{"ar", "0", Zero},
+		{"ar", "2", Two},
+		{"ar", "103", Few},
+		{"ar", "111", Many},
+		{"ar", "100", Other},
+		{"sr@latin", "2.3", Few},
+		{"cy", "6", Many},
+	} {
+		ops, err := ParseOperands(v.num)
+		if err != nil {
+			t.Fatalf("%s: %v", v.num, err)
+		}
+		if c := Cardinal(v.lang).Category(ops); c != v.cat {
+			t.Fatalf("%s/%s: expect = %v, got = %v", v.lang, v.num, v.cat, c)
+		}
+	}
+}
+
+func TestRules_Index(t *testing.T) {
+	ar := Cardinal("ar")
+	for i, c := range []Category{Zero, One, Two, Few, Many, Other} {
+		if x := ar.Index(c); x != i {
+			t.Fatalf("%v: expect = %d, got = %d", c, i, x)
+		}
+	}
+	if x := Cardinal("en").Index(Few); x != 1 {
+		t.Fatalf("expect = 1, got = %d", x)
+	}
+}
+
+func TestRules_Formula(t *testing.T) {
+	// the CLDR integer rules agree with the gettext formulas,
+	// after the index is clamped to nplurals
+	for _, lang := range []string{"en", "de", "fr", "ru", "uk", "pl", "cs", "ja"} {
+		forms, err := ParseForms(FormsTable[index(lang)].Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cldr := Cardinal(lang).Formula()
+		for n := 0; n <= 1000; n++ {
+			a, b := cldr(n), forms.Eval(n)
+			if a >= forms.NPlurals {
+				a = forms.NPlurals - 1
+			}
+			if a != b {
+				t.Fatalf("%s/%d: expect = %d, got = %d", lang, n, b, a)
+			}
+		}
+	}
+}
+
+func TestCategory_String(t *testing.T) {
+	for i, s := range []string{"zero", "one", "two", "few", "many", "other"} {
+		if c, err := ParseCategory(s); err != nil || c != Category(i) || c.String() != s {
+			t.Fatalf("%s: got = %v, %v", s, c, err)
+		}
+	}
+	if _, err := ParseCategory("more"); err == nil {
+		t.Fatal("expect error")
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	en := Ordinal("en_US")
+	for n, c := range map[int64]Category{
+		1: One, 2: Two, 3: Few, 4: Other,
+		11: Other, 12: Other, 13: Other,
+		21: One, 22: Two, 23: Few, 101: One, 111: Other,
+	} {
+		if x := en.IntCategory(n); x != c {
+			t.Fatalf("en/%d: expect = %v, got = %v", n, c, x)
+		}
+	}
+	if x := en.Index(Few); x != 2 {
+		t.Fatalf("expect = 2, got = %d", x)
+	}
+
+	for _, v := range []struct {
+		lang string
+		n    int64
+		cat  Category
+	}{
+		{"fr", 1, One},
+		{"fr", 2, Other},
+		{"sv", 2, One},
+		{"sv", 12, Other},
+		{"it", 8, Many},
+		{"it", 9, Other},
+		{"cy", 0, Zero},
+		{"zh_CN", 1, Other},
+	} {
+		if x := Ordinal(v.lang).IntCategory(v.n); x != v.cat {
+			t.Fatalf("%s/%d: expect = %v, got = %v", v.lang, v.n, v.cat, x)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"sync"
+)
+
+// formsCache maps the normalized Plural-Forms text (see fmtForms)
+// to its compiled *Forms, so every distinct expression is parsed once.
+// The raw text is cached too, so that the hot path doesn't allocate.
+var formsCache sync.Map
+
+func loadForms(forms string) (*Forms, error) {
+	if v, ok := formsCache.Load(forms); ok {
+		return v.(*Forms), nil
+	}
+	key := fmtForms(forms)
+	if v, ok := formsCache.Load(key); ok {
+		formsCache.Store(forms, v)
+		return v.(*Forms), nil
+	}
+	p, err := parseForms(forms)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := formsCache.LoadOrStore(key, p)
+	formsCache.Store(forms, v)
+	return v.(*Forms), nil
+}
+
+// compile turns a parsed expression into a closure tree.
+//
+// Constant sub-expressions are folded, and the conditions are compiled
+// to boolean closures, so the evaluation never allocates.
+func compile(x node) func(n int) int {
+	x = fold(x)
+	switch x := x.(type) {
+	case varNode:
+		return func(n int) int { return n }
+	case numNode:
+		v := x.val
+		return func(n int) int { return v }
+	case condNode:
+		cond, yes, no := compileBool(x.cond), compile(x.yes), compile(x.no)
+		return func(n int) int {
+			if cond(n) {
+				return yes(n)
+			}
+			return no(n)
+		}
+	case binaryNode:
+		if isBoolOp(x.op) {
+			break
+		}
+		return compileArith(x)
+	}
+	f := compileBool(x)
+	return func(n int) int {
+		if f(n) {
+			return 1
+		}
+		return 0
+	}
+}
+
+func compileArith(x binaryNode) func(n int) int {
+	// the most common shapes: "n % 10", "n / 10", ...
+	if _, ok := x.x.(varNode); ok {
+		if y, ok := x.y.(numNode); ok {
+			c := y.val
+			switch x.op {
+			case "%":
+				if c == 0 {
+					return func(n int) int { return 0 }
+				}
+				return func(n int) int { return n % c }
+			case "/":
+				if c == 0 {
+					return func(n int) int { return 0 }
+				}
+				return func(n int) int { return n / c }
+			case "+":
+				return func(n int) int { return n + c }
+			case "-":
+				return func(n int) int { return n - c }
+			case "*":
+				return func(n int) int { return n * c }
+			}
+		}
+	}
+
+	a, b := compile(x.x), compile(x.y)
+	switch x.op {
+	case "+":
+		return func(n int) int { return a(n) + b(n) }
+	case "-":
+		return func(n int) int { return a(n) - b(n) }
+	case "*":
+		return func(n int) int { return a(n) * b(n) }
+	case "/":
+		return func(n int) int {
+			if y := b(n); y != 0 {
+				return a(n) / y
+			}
+			return 0
+		}
+	case "%":
+		return func(n int) int {
+			if y := b(n); y != 0 {
+				return a(n) % y
+			}
+			return 0
+		}
+	}
+	panic("plural: unknown operator " + x.op)
+}
+
+func compileBool(x node) func(n int) bool {
+	switch x := x.(type) {
+	case notNode:
+		f := compileBool(x.x)
+		return func(n int) bool { return !f(n) }
+	case binaryNode:
+		switch x.op {
+		case "||":
+			a, b := compileBool(x.x), compileBool(x.y)
+			return func(n int) bool { return a(n) || b(n) }
+		case "&&":
+			a, b := compileBool(x.x), compileBool(x.y)
+			return func(n int) bool { return a(n) && b(n) }
+		case "==", "!=", "<", "<=", ">", ">=":
+			return compileCompare(x)
+		}
+	}
+	f := compile(x)
+	return func(n int) bool { return f(n) != 0 }
+}
+
+func compileCompare(x binaryNode) func(n int) bool {
+	a := compile(x.x)
+	if y, ok := x.y.(numNode); ok {
+		c := y.val
+		switch x.op {
+		case "==":
+			return func(n int) bool { return a(n) == c }
+		case "!=":
+			return func(n int) bool { return a(n) != c }
+		case "<":
+			return func(n int) bool { return a(n) < c }
+		case "<=":
+			return func(n int) bool { return a(n) <= c }
+		case ">":
+			return func(n int) bool { return a(n) > c }
+		case ">=":
+			return func(n int) bool { return a(n) >= c }
+		}
+	}
+
+	b := compile(x.y)
+	switch x.op {
+	case "==":
+		return func(n int) bool { return a(n) == b(n) }
+	case "!=":
+		return func(n int) bool { return a(n) != b(n) }
+	case "<":
+		return func(n int) bool { return a(n) < b(n) }
+	case "<=":
+		return func(n int) bool { return a(n) <= b(n) }
+	case ">":
+		return func(n int) bool { return a(n) > b(n) }
+	case ">=":
+		return func(n int) bool { return a(n) >= b(n) }
+	}
+	panic("plural: unknown operator " + x.op)
+}
+
+// fold evaluates the constant sub-expressions of x.
+func fold(x node) node {
+	switch v := x.(type) {
+	case notNode:
+		v.x = fold(v.x)
+		x = v
+	case binaryNode:
+		v.x, v.y = fold(v.x), fold(v.y)
+		x = v
+	case condNode:
+		v.cond, v.yes, v.no = fold(v.cond), fold(v.yes), fold(v.no)
+		if c, ok := v.cond.(numNode); ok {
+			if c.val != 0 {
+				return v.yes
+			}
+			return v.no
+		}
+		x = v
+	}
+	if isConst(x) {
+		return numNode{val: x.eval(0)}
+	}
+	return x
+}
+
+func isConst(x node) bool {
+	switch x := x.(type) {
+	case numNode:
+		return true
+	case notNode:
+		return isConst(x.x)
+	case binaryNode:
+		return isConst(x.x) && isConst(x.y)
+	case condNode:
+		return isConst(x.cond) && isConst(x.yes) && isConst(x.no)
+	}
+	return false
+}
+
+func isBoolOp(op string) bool {
+	switch op {
+	case "||", "&&", "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
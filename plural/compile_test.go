@@ -0,0 +1,122 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	exprs := []string{
+		"0",
+		"n",
+		"!n",
+		"!!n",
+		"n-1",
+		"2*3+n%7",
+		"(1 ? n : 2) == 3",
+		"0 ? 1 : n > 4",
+		"n % (n - 3)",
+		"n / (n - 3)",
+		"n == 1 ? 0 : 1 + 1 < 3 ? 1 : 2",
+		"n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2",
+	}
+	for _, s := range exprs {
+		x, err := parseExpr(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		f := compile(x)
+		for n := -200; n <= 200; n++ {
+			if a, b := f(n), x.eval(n); a != b {
+				t.Fatalf("%q/%d: expect = %d, got = %d", s, n, b, a)
+			}
+		}
+	}
+}
+
+func TestParseForms_cache(t *testing.T) {
+	a, err := ParseForms("nplurals=2; plural=(n != 1);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseForms("nplurals=2;plural=(n!=1);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Fatal("ParseForms must not share the returned *Forms")
+	}
+	if a.expr != b.expr {
+		t.Fatal("expect cached expression")
+	}
+
+	b.NPlurals = 3
+	if c, _ := ParseForms("nplurals=2; plural=(n != 1);"); c.NPlurals != 2 {
+		t.Fatalf("expect = 2, got = %d", c.NPlurals)
+	}
+}
+
+func TestForms_allocs(t *testing.T) {
+	for _, v := range FormsTable {
+		forms, err := ParseForms(v.Value)
+		if err != nil {
+			t.Fatalf("%s: %v", v.Lang, err)
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			for n := 0; n < 200; n++ {
+				forms.Eval(n)
+			}
+		})
+		if allocs != 0 {
+			t.Fatalf("%s: expect no allocation, got %v", v.Lang, allocs)
+		}
+	}
+}
+
+const benchForms = "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"
+
+func BenchmarkFormula_table(b *testing.B) {
+	f := formulaTable[fmtForms(benchForms)]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f(i)
+	}
+}
+
+func BenchmarkFormula_compiled(b *testing.B) {
+	forms, err := ParseForms(benchForms)
+	if err != nil {
+		b.Fatal(err)
+	}
+	f := forms.Formula()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f(i)
+	}
+}
+
+func BenchmarkFormula_tree(b *testing.B) {
+	forms, err := ParseForms(benchForms)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		forms.expr.eval(i)
+	}
+}
+
+func BenchmarkFormsFormula_cached(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FormsFormula(benchForms, "ru")
+	}
+}
+
+func BenchmarkParseForms_uncached(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		parseForms(benchForms)
+	}
+}
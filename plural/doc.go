@@ -31,6 +31,24 @@ Examples:
 		// ??: 8
 	}
 
+The Plural-Forms header of a PO/MO file can be compiled directly:
+
+	forms, err := plural.ParseForms("nplurals=3; plural=n==1 ? 0 : n==2 ? 1 : 2;")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(forms.NPlurals, forms.Eval(1), forms.Eval(2), forms.Eval(5))
+	// Output:
+	// 3 0 1 2
+
+The CLDR plural categories are supported too, for integer and decimal numbers:
+
+	ops, _ := plural.ParseOperands("1.5")
+	fmt.Println(plural.Cardinal("ru").Category(ops))       // other
+	fmt.Println(plural.Cardinal("ru").IntCategory(22))     // few
+	fmt.Println(plural.Cardinal("ru").Index(plural.Few))   // 1 (msgstr[1])
+
 See http://www.gnu.org/software/gettext/manual/html_node/Plural-forms.html
+See http://unicode.org/reports/tr35/tr35-numbers.html#Language_Plural_Rules
 */
 package plural
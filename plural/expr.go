@@ -0,0 +1,288 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// The plural expression grammar, as accepted by GNU's gettext (see
+// gettext-runtime/intl/plural.y), from the lowest to the highest precedence:
+//
+//	expr    = or ["?" expr ":" expr]
+//	or      = and {"||" and}
+//	and     = eq {"&&" eq}
+//	eq      = rel {("==" | "!=") rel}
+//	rel     = add {("<" | "<=" | ">" | ">=") add}
+//	add     = mul {("+" | "-") mul}
+//	mul     = unary {("*" | "/" | "%") unary}
+//	unary   = "!" unary | primary
+//	primary = "n" | number | "(" expr ")"
+
+// node is a parsed plural expression.
+type node interface {
+	eval(n int) int
+	String() string
+}
+
+type varNode struct{}
+
+type numNode struct {
+	val int
+}
+
+type notNode struct {
+	x node
+}
+
+type binaryNode struct {
+	op   string
+	x, y node
+}
+
+type condNode struct {
+	cond, yes, no node
+}
+
+func (varNode) eval(n int) int { return n }
+func (varNode) String() string { return "n" }
+
+func (p numNode) eval(n int) int { return p.val }
+func (p numNode) String() string { return strconv.Itoa(p.val) }
+
+func (p notNode) eval(n int) int { return boolToInt(p.x.eval(n) == 0) }
+func (p notNode) String() string { return "!" + p.x.String() }
+
+func (p binaryNode) eval(n int) int {
+	switch p.op {
+	case "||":
+		return boolToInt(p.x.eval(n) != 0 || p.y.eval(n) != 0)
+	case "&&":
+		return boolToInt(p.x.eval(n) != 0 && p.y.eval(n) != 0)
+	}
+	x, y := p.x.eval(n), p.y.eval(n)
+	switch p.op {
+	case "==":
+		return boolToInt(x == y)
+	case "!=":
+		return boolToInt(x != y)
+	case "<":
+		return boolToInt(x < y)
+	case "<=":
+		return boolToInt(x <= y)
+	case ">":
+		return boolToInt(x > y)
+	case ">=":
+		return boolToInt(x >= y)
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	case "/":
+		if y == 0 {
+			return 0
+		}
+		return x / y
+	case "%":
+		if y == 0 {
+			return 0
+		}
+		return x % y
+	}
+	panic("plural: unknown operator " + p.op)
+}
+
+func (p binaryNode) String() string {
+	return "(" + p.x.String() + " " + p.op + " " + p.y.String() + ")"
+}
+
+func (p condNode) eval(n int) int {
+	if p.cond.eval(n) != 0 {
+		return p.yes.eval(n)
+	}
+	return p.no.eval(n)
+}
+
+func (p condNode) String() string {
+	return "(" + p.cond.String() + " ? " + p.yes.String() + " : " + p.no.String() + ")"
+}
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// parseExpr parses a C-like plural expression, such as "(n != 1)".
+func parseExpr(s string) (node, error) {
+	p := &exprParser{src: s}
+	p.next()
+	x := p.parseCond()
+	if p.err == nil && p.tok != "" {
+		p.errorf("unexpected %q", p.tok)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return x, nil
+}
+
+type exprParser struct {
+	src string
+	pos int    // offset of the next unread byte
+	tok string // current token, "" at the end of input
+	off int    // offset of the current token
+	err error
+}
+
+func (p *exprParser) errorf(format string, a ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf("plural: %q: offset %d: %s", p.src, p.off, fmt.Sprintf(format, a...))
+	}
+	// stop scanning after the first error
+	p.tok, p.pos = "", len(p.src)
+}
+
+func (p *exprParser) next() {
+	for p.pos < len(p.src) && isSpace(p.src[p.pos]) {
+		p.pos++
+	}
+	p.off = p.pos
+	if p.pos >= len(p.src) {
+		p.tok = ""
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case isDigit(c):
+		end := p.pos
+		for end < len(p.src) && isDigit(p.src[end]) {
+			end++
+		}
+		p.tok, p.pos = p.src[p.pos:end], end
+		return
+	case c == 'n':
+		p.tok, p.pos = "n", p.pos+1
+		return
+	}
+
+	if p.pos+1 < len(p.src) {
+		switch op := p.src[p.pos : p.pos+2]; op {
+		case "||", "&&", "==", "!=", "<=", ">=":
+			p.tok, p.pos = op, p.pos+2
+			return
+		}
+	}
+	switch c {
+	case '?', ':', '<', '>', '+', '-', '*', '/', '%', '!', '(', ')':
+		p.tok, p.pos = string(c), p.pos+1
+		return
+	}
+	p.errorf("invalid character %q", c)
+}
+
+func (p *exprParser) expect(tok string) {
+	if p.tok != tok {
+		if p.tok == "" {
+			p.errorf("expected %q, found end of expression", tok)
+		} else {
+			p.errorf("expected %q, found %q", tok, p.tok)
+		}
+		return
+	}
+	p.next()
+}
+
+func (p *exprParser) parseCond() node {
+	x := p.parseBinary(0)
+	if p.tok != "?" {
+		return x
+	}
+	p.next()
+	yes := p.parseCond()
+	p.expect(":")
+	no := p.parseCond()
+	return condNode{cond: x, yes: yes, no: no}
+}
+
+// binaryOps lists the binary operators, from the lowest to the highest precedence.
+var binaryOps = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) parseBinary(prec int) node {
+	if prec == len(binaryOps) {
+		return p.parseUnary()
+	}
+	x := p.parseBinary(prec + 1)
+	for p.err == nil && isOneOf(p.tok, binaryOps[prec]) {
+		op := p.tok
+		p.next()
+		y := p.parseBinary(prec + 1)
+		x = binaryNode{op: op, x: x, y: y}
+	}
+	return x
+}
+
+func (p *exprParser) parseUnary() node {
+	if p.tok == "!" {
+		p.next()
+		return notNode{x: p.parseUnary()}
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() node {
+	switch tok := p.tok; {
+	case tok == "n":
+		p.next()
+		return varNode{}
+	case tok != "" && isDigit(tok[0]):
+		v, err := strconv.Atoi(tok)
+		if err != nil {
+			p.errorf("invalid number %q", tok)
+			return numNode{}
+		}
+		p.next()
+		return numNode{val: v}
+	case tok == "(":
+		p.next()
+		x := p.parseCond()
+		p.expect(")")
+		return x
+	case tok == "":
+		p.errorf("unexpected end of expression")
+	default:
+		p.errorf("unexpected %q", tok)
+	}
+	return numNode{}
+}
+
+func isOneOf(s string, list []string) bool {
+	for _, v := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
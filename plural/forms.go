@@ -0,0 +1,112 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Forms is a parsed Plural-Forms header value, such as
+// "nplurals=2; plural=(n != 1);".
+//
+// See http://www.gnu.org/software/gettext/manual/html_node/Plural-forms.html
+type Forms struct {
+	NPlurals int    // nplurals=N
+	Plural   string // plural=EXPRESSION
+	expr     node
+	fn       func(n int) int
+}
+
+// ParseForms parses a Plural-Forms header value.
+//
+// The plural expression is a C expression over the variable n,
+// with the ?:, ||, &&, ==, !=, <, <=, >, >=, +, -, *, /, % and ! operators.
+//
+// The expression is compiled once for every distinct header value,
+// later calls return the cached result.
+func ParseForms(forms string) (*Forms, error) {
+	p, err := loadForms(forms)
+	if err != nil {
+		return nil, err
+	}
+	q := *p
+	return &q, nil
+}
+
+func parseForms(forms string) (*Forms, error) {
+	var (
+		nplurals, plural       string
+		hasNPlurals, hasPlural bool
+	)
+	for _, s := range strings.Split(forms, ";") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		idx := strings.Index(s, "=")
+		if idx < 0 {
+			return nil, fmt.Errorf("plural: %q: invalid field %q", forms, s)
+		}
+		key, val := strings.TrimSpace(s[:idx]), strings.TrimSpace(s[idx+1:])
+		switch key {
+		case "nplurals":
+			nplurals, hasNPlurals = val, true
+		case "plural":
+			plural, hasPlural = val, true
+		default:
+			return nil, fmt.Errorf("plural: %q: unknown field %q", forms, key)
+		}
+	}
+	if !hasNPlurals {
+		return nil, fmt.Errorf("plural: %q: missing nplurals", forms)
+	}
+	if !hasPlural {
+		return nil, fmt.Errorf("plural: %q: missing plural", forms)
+	}
+
+	n, err := strconv.Atoi(nplurals)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("plural: %q: invalid nplurals %q", forms, nplurals)
+	}
+	expr, err := parseExpr(plural)
+	if err != nil {
+		return nil, err
+	}
+	return &Forms{NPlurals: n, Plural: plural, expr: expr, fn: compile(expr)}, nil
+}
+
+// Eval returns the plural form index for n.
+//
+// The result is not clamped to NPlurals.
+func (p *Forms) Eval(n int) int {
+	return p.fn(n)
+}
+
+// Formula returns the plural formula as a function.
+func (p *Forms) Formula() func(n int) int {
+	return p.fn
+}
+
+// String returns the Plural-Forms header value.
+func (p *Forms) String() string {
+	return fmt.Sprintf("nplurals=%d; plural=%s;", p.NPlurals, p.Plural)
+}
+
+// FormsFormula returns the plural formula of a Plural-Forms header value.
+//
+// If the header is empty or invalid, the language's standard plural
+// formula is used instead, see Formula.
+func FormsFormula(forms, lang string) func(n int) int {
+	if forms != "" {
+		if p, err := loadForms(forms); err == nil {
+			return p.fn
+		}
+	}
+	if lang == "" {
+		lang = "??"
+	}
+	return Formula(lang)
+}
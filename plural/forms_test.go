@@ -0,0 +1,101 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"testing"
+)
+
+func TestParseForms_table(t *testing.T) {
+	for _, v := range FormsTable {
+		forms, err := ParseForms(v.Value)
+		if err != nil {
+			t.Fatalf("%s: %v", v.Lang, err)
+		}
+		// the CLDR entries have no hand-written formula
+		formula, ok := formulaTable[fmtForms(v.Value)]
+		if !ok {
+			formula = forms.Eval
+		}
+		for n := 0; n <= 1000; n++ {
+			if a, b := forms.Eval(n), formula(n); a != b {
+				t.Fatalf("%s/%d: expect = %d, got = %d", v.Lang, n, b, a)
+			}
+			if x := forms.Eval(n); x < 0 || x >= forms.NPlurals {
+				t.Fatalf("%s/%d: %d out of range", v.Lang, n, x)
+			}
+		}
+	}
+}
+
+func TestParseForms(t *testing.T) {
+	for i, v := range testFormsData {
+		forms, err := ParseForms(v.forms)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if forms.NPlurals != v.nplurals {
+			t.Fatalf("%d: nplurals: expect = %d, got = %d", i, v.nplurals, forms.NPlurals)
+		}
+		for n, out := range v.out {
+			if x := forms.Eval(n); x != out {
+				t.Fatalf("%d/%d: expect = %d, got = %d", i, n, out, x)
+			}
+		}
+	}
+}
+
+func TestParseForms_error(t *testing.T) {
+	for i, s := range []string{
+		"",
+		"nplurals=2;",
+		"plural=n != 1;",
+		"nplurals=0; plural=0;",
+		"nplurals=x; plural=0;",
+		"nplurals=2; plural=;",
+		"nplurals=2; plural=(n != 1;",
+		"nplurals=2; plural=n != 1);",
+		"nplurals=2; plural=n ? 1;",
+		"nplurals=2; plural=n = 1;",
+		"nplurals=2; plural=m != 1;",
+		"nplurals=2; plural=n != 1; extra=1;",
+	} {
+		if _, err := ParseForms(s); err == nil {
+			t.Fatalf("%d: %q: expect error", i, s)
+		}
+	}
+}
+
+func TestFormsFormula(t *testing.T) {
+	// header wins over the language table
+	f := FormsFormula("nplurals=3; plural=n==1 ? 0 : n==2 ? 1 : 2;", "zh_CN")
+	if a, b, c := f(1), f(2), f(5); a != 0 || b != 1 || c != 2 {
+		t.Fatalf("expect = 0 1 2, got = %d %d %d", a, b, c)
+	}
+
+	// invalid or missing header falls back to the language table
+	for _, forms := range []string{"", "nplurals=2; plural=n +;"} {
+		f := FormsFormula(forms, "fr")
+		if a, b := f(1), f(2); a != 0 || b != 1 {
+			t.Fatalf("%q: expect = 0 1, got = %d %d", forms, a, b)
+		}
+	}
+}
+
+var testFormsData = []struct {
+	forms    string
+	nplurals int
+	out      []int // out[n]
+}{
+	{"nplurals=1; plural=0;", 1, []int{0, 0, 0}},
+	{"nplurals=2;plural=n!=1", 2, []int{1, 0, 1, 1}},
+	{" nplurals = 2 ; plural = !(n == 1) ; ", 2, []int{1, 0, 1}},
+	{"nplurals=3; plural=n>=2+1 ? 2 : n*2/2-1+1 == 1;", 3, []int{0, 1, 0, 2, 2}},
+	{"nplurals=3; plural=n==0 ? 0 : n==1 ? 1 : 2;", 3, []int{0, 1, 2, 2}},
+	{"nplurals=2; plural=n%0 || n/0;", 2, []int{0, 0}},
+	{"nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);", 6, []int{
+		0, 1, 2, 3, 3, 3, 3, 3, 3, 3, 3, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
+	}},
+}
@@ -8,23 +8,55 @@ import (
 	"strings"
 )
 
+//go:generate go run gen.go
+
 // Formula provides the language's standard plural formula.
+//
+// The language is a POSIX locale name or a BCP 47 language tag, such as
+// "pt_BR", "pt-BR", "pt_BR.UTF-8" or "sr@latin". The most specific entry
+// of FormsTable is used: "pt_BR" uses the "pt_BR" entry, "pt_PT" uses "pt".
 func Formula(lang string) func(n int) int {
 	if idx := index(lang); idx != -1 {
-		return formulaTable[fmtForms(FormsTable[idx].Value)]
+		return tableFormula(FormsTable[idx].Value)
 	}
 	if idx := index("??"); idx != -1 {
-		return formulaTable[fmtForms(FormsTable[idx].Value)]
+		return tableFormula(FormsTable[idx].Value)
 	}
 	return func(n int) int {
 		return n
 	}
 }
 
+// TableForms returns the Plural-Forms of the language's FormsTable entry,
+// such as "nplurals=2; plural=(n != 1);" for "en_US". The entry is looked up
+// like Formula does, "" is returned if the language has none.
+func TableForms(lang string) string {
+	if idx := index(lang); idx != -1 {
+		return FormsTable[idx].Value
+	}
+	return ""
+}
+
+// tableFormula returns the hand-written formula of the FormsTable value,
+// or the compiled expression if there is none.
+func tableFormula(forms string) func(n int) int {
+	if fn, ok := formulaTable[fmtForms(forms)]; ok {
+		return fn
+	}
+	if p, err := loadForms(forms); err == nil {
+		return p.fn
+	}
+	return formulaTable[fmtForms("nplurals=1; plural=0;")]
+}
+
+// index returns the index of the most specific FormsTable entry
+// of the language, or -1 if there is none.
 func index(lang string) int {
-	for i := 0; i < len(FormsTable); i++ {
-		if strings.HasPrefix(lang, FormsTable[i].Lang) {
-			return i
+	for _, key := range localeKeys(lang) {
+		for i := 0; i < len(FormsTable); i++ {
+			if strings.EqualFold(FormsTable[i].Lang, key) {
+				return i
+			}
 		}
 	}
 	return -1
@@ -16,6 +16,89 @@ func TestFormula(t *testing.T) {
 	}
 }
 
+func TestFormula_lookup(t *testing.T) {
+	for _, v := range []struct {
+		lang   string
+		expect string
+	}{
+		{"pt", "pt"},
+		{"pt_BR", "pt_BR"},
+		{"pt-BR", "pt_BR"},
+		{"pt_br", "pt_BR"},
+		{"pt_BR.UTF-8", "pt_BR"},
+		{"pt_PT", "pt"},
+		{"sr@latin", "sr"},
+		{"sr_RS.UTF-8@latin", "sr"},
+		{"zh-Hant-TW", "zh"},
+		{"en-US-u-ca-gregory", "en"},
+		{"iw_IL", "he"},
+		{"cy", "cy"},
+		{"english", ""},
+		{"", ""},
+	} {
+		var lang string
+		if idx := index(v.lang); idx != -1 {
+			lang = FormsTable[idx].Lang
+		}
+		if lang != v.expect {
+			t.Fatalf("%q: expect = %q, got = %q", v.lang, v.expect, lang)
+		}
+	}
+}
+
+func TestTableForms(t *testing.T) {
+	if s := TableForms("en_US"); s != "nplurals=2; plural=(n != 1);" {
+		t.Fatalf("en_US: got = %q", s)
+	}
+	if s := TableForms("english"); s != "" {
+		t.Fatalf("english: got = %q", s)
+	}
+}
+
+func TestFormsTable_cldr(t *testing.T) {
+	// the formulas derived from CLDR select the same forms as the CLDR rules
+	for _, v := range FormsTable {
+		if _, ok := formulaTable[fmtForms(v.Value)]; ok {
+			continue
+		}
+		rules, formula := Cardinal(v.Lang), Formula(v.Lang)
+		forms := make(map[int]Category)
+		cats := make(map[Category]int)
+		for n := 0; n <= 1000; n++ {
+			i, c := formula(n), rules.IntCategory(int64(n))
+			if x, ok := forms[i]; ok && x != c {
+				t.Fatalf("%s/%d: form %d is %v and %v", v.Lang, n, i, x, c)
+			}
+			if x, ok := cats[c]; ok && x != i {
+				t.Fatalf("%s/%d: %v is form %d and %d", v.Lang, n, c, x, i)
+			}
+			forms[i], cats[c] = c, i
+		}
+	}
+}
+
+func TestFormula_cldr(t *testing.T) {
+	for _, v := range []struct {
+		lang string
+		out  []int
+	}{
+		{"cy", []int{0, 1, 2, 3, 5, 5, 4, 5}},
+		{"gd", []int{3, 0, 1, 2, 2, 2, 2, 2, 2, 2, 2, 0, 1, 2}},
+		{"is", []int{1, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		{"ca", []int{2, 0, 2}},
+	} {
+		formula := Formula(v.lang)
+		for n, out := range v.out {
+			if x := formula(n); x != out {
+				t.Fatalf("%s/%d: expect = %d, got = %d", v.lang, n, out, x)
+			}
+		}
+	}
+	if x := Formula("ca")(1000000); x != 1 {
+		t.Fatalf("ca/1000000: expect = 1, got = %d", x)
+	}
+}
+
 var testData = []struct {
 	lang string
 	in   int
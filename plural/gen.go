@@ -0,0 +1,417 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+// +build ignore
+
+// This program generates table.go and cldr_table.go from the data files,
+// run "go generate" in the plural directory, see data/README.
+//
+// The GNU gettext tables (plural-table.c and lang-table.c) are GPL-3, so
+// they aren't in this repository: the -gnu flag is the gettext-tools/src
+// dir of a GNU gettext source tree, see data/README.
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = `// Copyright 2013 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by gen.go; DO NOT EDIT.
+
+package plural
+`
+
+// maxCheckedN is the largest n used to find the categories of the integers,
+// the CLDR rules use n % 1000000 at most.
+const maxCheckedN = 2000000
+
+type formsEntry struct {
+	Lang, Language, Value string
+}
+
+type pluralRules struct {
+	Locales string `xml:"locales,attr"`
+	Rules   []struct {
+		Count string `xml:"count,attr"`
+		Rule  string `xml:",chardata"`
+	} `xml:"pluralRule"`
+}
+
+var gnuDir = flag.String("gnu", "data", "the dir of GNU gettext's plural-table.c and lang-table.c")
+
+func main() {
+	flag.Parse()
+	gnuTable := parseCTable(filepath.Join(*gnuDir, "plural-table.c"), 3)
+	langTable := parseCTable(filepath.Join(*gnuDir, "lang-table.c"), 2)
+	cardinal := parsePluralsXML("data/plurals.xml")
+	ordinal := parsePluralsXML("data/ordinals.xml")
+
+	names := make(map[string]string)
+	for _, v := range langTable {
+		names[v[0]] = v[1]
+	}
+
+	var gnu, cldr []formsEntry
+	var known = make(map[string]bool)
+	for _, v := range gnuTable {
+		gnu = append(gnu, formsEntry{v[0], v[1], v[2]})
+		known[strings.ToLower(v[0])] = true
+	}
+	for _, v := range cardinal {
+		value := ""
+		for _, locale := range strings.Fields(v.Locales) {
+			lang := strings.ToLower(locale)
+			if idx := strings.Index(lang, "_"); idx != -1 {
+				lang = lang[:idx]
+			}
+			if known[strings.ToLower(locale)] || known[lang] || names[locale] == "" {
+				continue
+			}
+			if value == "" {
+				value = deriveForms(v)
+			}
+			cldr = append(cldr, formsEntry{locale, names[locale], value})
+		}
+	}
+	sort.Slice(cldr, func(i, j int) bool { return cldr[i].Lang < cldr[j].Lang })
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.WriteString(`
+// FormsTable are standard hard-coded plural rules.
+// The application developers and the translators need to understand them.
+//
+// The languages of GNU's gettext table come first, the other languages
+// use a formula derived from the CLDR integer rules.
+//
+// See GNU's gettext library source code: gettext/gettext-tools/src/plural-table.c
+// See CLDR 42: common/supplemental/plurals.xml
+var FormsTable = []struct {
+	Lang     string
+	Language string
+	Value    string
+}{
+	{"??", "Unknown", "nplurals=1; plural=0;"},
+
+	// GNU gettext: gettext-tools/src/plural-table.c
+`)
+	for _, v := range gnu {
+		fmt.Fprintf(&buf, "\t{%q, %q, %q},\n", v.Lang, v.Language, v.Value)
+	}
+	buf.WriteString("\n\t// CLDR 42: common/supplemental/plurals.xml\n")
+	for _, v := range cldr {
+		fmt.Fprintf(&buf, "\t{%q, %q, %q},\n", v.Lang, v.Language, v.Value)
+	}
+	buf.WriteString("}\n")
+	writeFile("table.go", buf.Bytes())
+
+	buf.Reset()
+	buf.WriteString(strings.Replace(header, "2013", "2020", 1))
+	buf.WriteString(`
+type cldrTableEntry struct {
+	Locales string   // space separated CLDR locales
+	Rules   []string // "category: rule", in order, without "other"
+}
+`)
+	writeCldrTable(&buf, "cldrCardinalTable", "cardinal", "plurals.xml", cardinal)
+	writeCldrTable(&buf, "cldrOrdinalTable", "ordinal", "ordinals.xml", ordinal)
+	writeFile("cldr_table.go", buf.Bytes())
+}
+
+func writeCldrTable(buf *bytes.Buffer, name, kind, file string, table []pluralRules) {
+	fmt.Fprintf(buf, "\n// %s are the CLDR %s plural rules.\n", name, kind)
+	fmt.Fprintf(buf, "//\n// See CLDR 42: common/supplemental/%s\n", file)
+	fmt.Fprintf(buf, "var %s = []cldrTableEntry{\n", name)
+	for _, v := range table {
+		var rules []string
+		for _, r := range v.Rules {
+			if r.Count != "other" {
+				rules = append(rules, r.Count+": "+trimSamples(r.Rule))
+			}
+		}
+		if len(rules) == 0 {
+			fmt.Fprintf(buf, "\t{%q, nil},\n", v.Locales)
+			continue
+		}
+		fmt.Fprintf(buf, "\t{%q, []string{\n", v.Locales)
+		for _, s := range rules {
+			fmt.Fprintf(buf, "\t\t%q,\n", s)
+		}
+		buf.WriteString("\t}},\n")
+	}
+	buf.WriteString("}\n")
+}
+
+func writeFile(name string, data []byte) {
+	src, err := format.Source(data)
+	if err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	if err := ioutil.WriteFile(name, src, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseCTable returns the string fields of the { "a", "b", ... } entries.
+func parseCTable(name string, fields int) [][]string {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		log.Fatalf("%v (see data/README: the GNU gettext files aren't in the repository)", err)
+	}
+	re := regexp.MustCompile(`\{\s*"[^"]*"(?:\s*,\s*"[^"]*")*\s*\}`)
+	field := regexp.MustCompile(`"([^"]*)"`)
+
+	var table [][]string
+	for _, s := range re.FindAllString(string(data), -1) {
+		var v []string
+		for _, m := range field.FindAllStringSubmatch(s, -1) {
+			v = append(v, m[1])
+		}
+		if len(v) != fields {
+			log.Fatalf("%s: invalid entry %s", name, s)
+		}
+		table = append(table, v)
+	}
+	return table
+}
+
+func parsePluralsXML(name string) []pluralRules {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var v struct {
+		Plurals struct {
+			Rules []pluralRules `xml:"pluralRules"`
+		} `xml:"plurals"`
+	}
+	if err := xml.Unmarshal(data, &v); err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+	return v.Plurals.Rules
+}
+
+func trimSamples(rule string) string {
+	if idx := strings.Index(rule, "@"); idx != -1 {
+		rule = rule[:idx]
+	}
+	return strings.TrimSpace(rule)
+}
+
+// relation is a CLDR relation, such as "n % 10 = 2..4".
+type relation struct {
+	operand string
+	mod     int
+	not     bool
+	ranges  [][2]int
+}
+
+var relationRe = regexp.MustCompile(`^([niftvwec])\s*(?:%\s*(\d+))?\s*(!=|=)\s*([\d.,]+)$`)
+
+// parseRule parses a rule as an "or" list of "and" lists.
+func parseRule(rule string) [][]relation {
+	var or [][]relation
+	for _, s := range strings.Split(trimSamples(rule), " or ") {
+		var and []relation
+		for _, s := range strings.Split(s, " and ") {
+			m := relationRe.FindStringSubmatch(strings.TrimSpace(s))
+			if m == nil {
+				log.Fatalf("invalid CLDR relation %q", s)
+			}
+			r := relation{operand: m[1], not: m[3] == "!="}
+			if m[2] != "" {
+				r.mod = atoi(m[2])
+			}
+			for _, s := range strings.Split(m[4], ",") {
+				lo, hi := s, s
+				if idx := strings.Index(s, ".."); idx != -1 {
+					lo, hi = s[:idx], s[idx+2:]
+				}
+				r.ranges = append(r.ranges, [2]int{atoi(lo), atoi(hi)})
+			}
+			and = append(and, r)
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+func atoi(s string) int {
+	x, err := strconv.Atoi(s)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return x
+}
+
+// value returns the operand of the integer n, the fraction digits
+// and the exponent of an integer are zero.
+func (r relation) value(n int) int {
+	if r.operand != "n" && r.operand != "i" {
+		return 0
+	}
+	if r.mod != 0 {
+		return n % r.mod
+	}
+	return n
+}
+
+func (r relation) match(n int) bool {
+	x := r.value(n)
+	for _, v := range r.ranges {
+		if x >= v[0] && x <= v[1] {
+			return !r.not
+		}
+	}
+	return r.not
+}
+
+// expr returns the C expression of the relation,
+// or "0"/"1" if it's a constant for the integers.
+func (r relation) expr() string {
+	if r.operand != "n" && r.operand != "i" {
+		if r.match(0) {
+			return "1"
+		}
+		return "0"
+	}
+	x := "n"
+	if r.mod != 0 {
+		x = fmt.Sprintf("n %% %d", r.mod)
+	}
+	var terms []string
+	for _, v := range r.ranges {
+		switch {
+		case v[0] == v[1] && r.not:
+			terms = append(terms, fmt.Sprintf("%s != %d", x, v[0]))
+		case v[0] == v[1]:
+			terms = append(terms, fmt.Sprintf("%s == %d", x, v[0]))
+		case r.not:
+			terms = append(terms, fmt.Sprintf("(%s < %d || %s > %d)", x, v[0], x, v[1]))
+		default:
+			terms = append(terms, fmt.Sprintf("%s >= %d && %s <= %d", x, v[0], x, v[1]))
+		}
+	}
+	if r.not {
+		return strings.Join(terms, " && ")
+	}
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return "(" + strings.Join(terms, " || ") + ")"
+}
+
+// condExpr returns the C expression of a rule, "0" if it's false
+// and "1" if it's true for all the integers.
+func condExpr(rule [][]relation) string {
+	var or []string
+	for _, and := range rule {
+		var terms []string
+		isFalse := false
+		for _, r := range and {
+			switch s := r.expr(); s {
+			case "0":
+				isFalse = true
+			case "1":
+			default:
+				terms = append(terms, s)
+			}
+		}
+		if isFalse {
+			continue
+		}
+		if len(terms) == 0 {
+			return "1"
+		}
+		or = append(or, strings.Join(terms, " && "))
+	}
+	if len(or) == 0 {
+		return "0"
+	}
+	if len(or) == 1 {
+		return or[0]
+	}
+	for i, s := range or {
+		if strings.Contains(s, "&&") {
+			or[i] = "(" + s + ")"
+		}
+	}
+	return strings.Join(or, " || ")
+}
+
+// deriveForms returns the gettext Plural-Forms of the CLDR rules.
+//
+// The categories which are not selected by any n in 0..maxCheckedN
+// are dropped, "other" is the last plural form.
+func deriveForms(v pluralRules) string {
+	var rules [][][]relation
+	for _, r := range v.Rules {
+		if r.Count != "other" {
+			rules = append(rules, parseRule(r.Rule))
+		}
+	}
+
+	used := make([]bool, len(rules)+1)
+	for n := 0; n <= maxCheckedN; n++ {
+		i := 0
+		for ; i < len(rules); i++ {
+			if matchRule(rules[i], n) {
+				break
+			}
+		}
+		used[i] = true
+	}
+
+	var conds []string
+	for i, rule := range rules {
+		if used[i] {
+			conds = append(conds, condExpr(rule))
+		}
+	}
+	if !used[len(rules)] && len(conds) > 0 {
+		conds = conds[:len(conds)-1]
+	}
+	if len(conds) == 0 {
+		return "nplurals=1; plural=0;"
+	}
+	if len(conds) == 1 {
+		return fmt.Sprintf("nplurals=2; plural=(%s ? 0 : 1);", conds[0])
+	}
+	var plural string
+	for i, s := range conds {
+		plural += fmt.Sprintf("%s ? %d : ", s, i)
+	}
+	return fmt.Sprintf("nplurals=%d; plural=%s%d;", len(conds)+1, plural, len(conds))
+}
+
+func matchRule(rule [][]relation, n int) bool {
+	for _, and := range rule {
+		ok := true
+		for _, r := range and {
+			if !r.match(n) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"strings"
+)
+
+// langAliases maps the deprecated language codes to the current ones.
+var langAliases = map[string]string{
+	"in": "id", // Indonesian
+	"iw": "he", // Hebrew
+	"ji": "yi", // Yiddish
+	"jw": "jv", // Javanese
+	"mo": "ro", // Moldavian
+	"sh": "sr", // Serbo-Croatian
+	"tl": "fil",
+}
+
+// localeKeys returns the lookup keys of a POSIX locale name or a BCP 47
+// language tag, from the most specific to the least one:
+//
+//	"pt-BR"                => ["pt_br", "pt"]
+//	"sr_RS.UTF-8@latin"    => ["sr_rs@latin", "sr_rs", "sr@latin", "sr"]
+//	"zh-Hant-TW-u-nu-hans" => ["zh_hant_tw", "zh_hant", "zh"]
+//
+// The keys are lower case, and the deprecated language codes
+// (such as "iw") are replaced by the current ones ("he").
+func localeKeys(lang string) []string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+
+	var modifier string
+	if idx := strings.Index(lang, "@"); idx != -1 {
+		lang, modifier = lang[:idx], lang[idx+1:]
+	}
+	if idx := strings.Index(lang, "."); idx != -1 {
+		lang = lang[:idx] // codeset
+	}
+
+	var subtags []string
+	for _, s := range strings.FieldsFunc(lang, func(r rune) bool { return r == '_' || r == '-' }) {
+		if len(subtags) != 0 && len(s) == 1 {
+			break // BCP 47 extension, such as "-u-nu-hans"
+		}
+		subtags = append(subtags, s)
+	}
+	if len(subtags) == 0 {
+		return nil
+	}
+	if s, ok := langAliases[subtags[0]]; ok {
+		subtags[0] = s
+	}
+
+	var keys []string
+	for i := len(subtags); i > 0; i-- {
+		key := strings.Join(subtags[:i], "_")
+		if modifier != "" {
+			keys = append(keys, key+"@"+modifier)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
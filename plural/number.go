@@ -0,0 +1,155 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"strconv"
+)
+
+// maxInt is the largest int, 1<<31-1 or 1<<63-1.
+const maxInt = int(^uint(0) >> 1)
+
+// Int64 returns n as the argument of a plural formula.
+//
+// The numbers which don't fit an int are replaced by n%1000000+1000000,
+// as GNU's gettext manual recommends for the large numbers: it keeps the
+// last digits (n%10, n%100, ...) and the magnitude (n > 1) of n,
+// which is what the plural formulas use. The negative numbers are
+// reduced like their absolute values, so Int64(-n) == -Int64(n).
+func Int64(n int64) int {
+	if int64(int(n)) == n {
+		return int(n)
+	}
+	return int(reduceInt64(n))
+}
+
+// reduceInt64 returns n%1000000+1000000, or -(-n%1000000+1000000)
+// if n is negative (without negating n, which overflows for MinInt64).
+func reduceInt64(n int64) int64 {
+	if n < 0 {
+		return n%1000000 - 1000000
+	}
+	return n%1000000 + 1000000
+}
+
+// Uint64 is like Int64, but for an unsigned number.
+func Uint64(n uint64) int {
+	if n <= uint64(maxInt) {
+		return int(n)
+	}
+	return int(n%1000000 + 1000000)
+}
+
+// UintOperands returns the operands of an unsigned integer.
+func UintOperands(n uint64) Operands {
+	return Operands{N: float64(n), I: n}
+}
+
+// IsInt reports whether the operands have no visible fraction digits,
+// so "1" is an integer, but "1.0" is not.
+func (p Operands) IsInt() bool {
+	return p.V == 0
+}
+
+// String returns the decimal number of the operands (without sign).
+func (p Operands) String() string {
+	s := strconv.FormatUint(p.I, 10)
+	if p.V > 0 {
+		f := strconv.FormatUint(p.F, 10)
+		for len(f) < p.V {
+			f = "0" + f
+		}
+		s += "." + f
+	}
+	return s
+}
+
+// DecimalIndex returns the gettext msgstr index of a decimal number.
+//
+// The integers are evaluated with the gettext formula (see Uint64).
+// The numbers with visible fraction digits (such as "1.5") or in the compact
+// notation (such as "1.2c6") are not defined by the gettext formulas, they
+// use the CLDR cardinal rules of the language mapped to the formula's
+// index, see DecimalFormula. If rules is nil, the integer digits are used.
+//
+// The categories are mapped on each call, use DecimalFormula to
+// select the forms of many numbers.
+func DecimalIndex(formula func(n int) int, rules *Rules, ops Operands) int {
+	return DecimalFormula(formula, rules)(ops)
+}
+
+// DecimalFormula returns the DecimalIndex func of the gettext formula
+// and the CLDR rules, the categories are mapped to the formula's indexes
+// once.
+//
+// A category has the index of its integers (the "few" of ru has the
+// index of 2, 3 and 4). A category which has no integers (such as the
+// "many" of cs, or the "other" of ru) has the index of "other", but the
+// "other" of the languages in decimalOther has the index of that category.
+func DecimalFormula(formula func(n int) int, rules *Rules) func(ops Operands) int {
+	if rules == nil {
+		return func(ops Operands) int {
+			return formula(Uint64(ops.I))
+		}
+	}
+	index := rules.decimalIndexes(formula)
+	return func(ops Operands) int {
+		if ops.IsInt() && ops.E == 0 {
+			return formula(Uint64(ops.I))
+		}
+		return index[rules.Category(ops)]
+	}
+}
+
+// decimalOther is the category whose index is used by the "other"
+// (fraction) numbers of the languages where no integer is "other",
+// as the GNU catalogs do: "1,5 файла" has the form of "2 файла".
+var decimalOther = map[string]Category{
+	"be": Few,
+	"pl": Few,
+	"ru": Few,
+	"uk": Few,
+}
+
+// decimalSamples are the integers evaluated to map the categories,
+// the millions are the "many" of fr, es, it and pt.
+var decimalSamples = func() []int {
+	var ss []int
+	for n := 0; n <= 1000; n++ {
+		ss = append(ss, n)
+	}
+	return append(ss, 1000000, 2000000)
+}()
+
+// decimalIndexes returns the formula's index of each category,
+// see DecimalFormula.
+func (p *Rules) decimalIndexes(formula func(n int) int) (index [Other + 1]int) {
+	for i := range index {
+		index[i] = -1
+	}
+	maxIndex := 0
+	for _, n := range decimalSamples {
+		x := formula(n)
+		if c := p.IntCategory(int64(n)); index[c] < 0 {
+			index[c] = x
+		}
+		if x > maxIndex {
+			maxIndex = x
+		}
+	}
+	if index[Other] < 0 {
+		if c, ok := decimalOther[p.Locale]; ok && index[c] >= 0 {
+			index[Other] = index[c]
+		} else {
+			index[Other] = maxIndex
+		}
+	}
+	for i := range index {
+		if index[i] < 0 {
+			index[i] = index[Other]
+		}
+	}
+	return index
+}
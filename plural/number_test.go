@@ -0,0 +1,134 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plural
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUint64(t *testing.T) {
+	if maxInt == math.MaxInt64 {
+		if x := Int64(math.MaxInt64); x != math.MaxInt64 {
+			t.Fatalf("expect = %d, got = %d", int64(math.MaxInt64), x)
+		}
+	}
+	for _, v := range []struct {
+		in  uint64
+		out int
+	}{
+		{0, 0},
+		{1, 1},
+		{1000001, 1000001},
+		{math.MaxUint64, 1551615},       // 18446744073709551615
+		{math.MaxUint64 - 4, 1551611},   // ...11
+		{10000000000000000000, 1000000}, // 10^19
+		{10000000000000000001, 1000001}, // 10^19 + 1
+		{10000000000000000021, 1000021}, // 10^19 + 21
+		{10000000000000000011, 1000011}, // 10^19 + 11
+		{12345678901234567890, 1567890}, // ...567890
+		{18000000000000000002, 1000002}, // ...000002
+		{18000000000000000000 + 999999, 1999999},
+	} {
+		if x := Uint64(v.in); x != v.out {
+			t.Fatalf("%d: expect = %d, got = %d", v.in, v.out, x)
+		}
+	}
+
+	// the reduced number keeps the plural form
+	ru := Formula("ru")
+	for _, n := range []uint64{10000000000000000001, 10000000000000000002, 10000000000000000005, 10000000000000000011} {
+		if a, b := ru(Uint64(n)), ru(int(n%100)); a != b {
+			t.Fatalf("%d: expect = %d, got = %d", n, b, a)
+		}
+	}
+}
+
+func TestInt64(t *testing.T) {
+	for _, n := range []int64{0, 1, -1, 5, -5, math.MaxInt32, math.MinInt32} {
+		if x := Int64(n); int64(x) != n {
+			t.Fatalf("%d: expect = %d, got = %d", n, n, x)
+		}
+	}
+
+	// the numbers out of a 32-bit int are reduced by reduceInt64
+	for _, v := range []struct {
+		in  int64
+		out int64
+	}{
+		{1 << 31, 1483648},        // 2147483648
+		{-1<<31 - 1, -1483649},    // -2147483649
+		{3000000001, 1000001},     // ...000001
+		{-3000000001, -1000001},   // ...000001
+		{-3000000000, -1000000},   // ...000000
+		{-3000999999, -1999999},   // ...999999
+		{math.MaxInt64, 1775807},  // 9223372036854775807
+		{math.MinInt64, -1775808}, // -9223372036854775808
+		{math.MinInt64 + 1, -1775807},
+	} {
+		if x := reduceInt64(v.in); x != v.out {
+			t.Fatalf("%d: expect = %d, got = %d", v.in, v.out, x)
+		}
+		if v.in != math.MinInt64 && reduceInt64(-v.in) != -v.out {
+			t.Fatalf("%d: expect = %d, got = %d", -v.in, -v.out, reduceInt64(-v.in))
+		}
+	}
+
+	// the reduced negative number keeps the plural form of its absolute value
+	ru := Formula("ru")
+	for _, n := range []int64{-3000000001, -3000000002, -3000000005, -3000000011} {
+		if a, b := ru(-int(reduceInt64(n))), ru(int(-n%100)); a != b {
+			t.Fatalf("%d: expect = %d, got = %d", n, b, a)
+		}
+	}
+}
+
+func TestDecimalIndex(t *testing.T) {
+	en, fr, ru, pl, cs := Formula("en"), Formula("fr"), Formula("ru"), Formula("pl"), Formula("cs")
+	for _, v := range []struct {
+		formula func(n int) int
+		rules   *Rules
+		num     string
+		index   int
+	}{
+		{en, Cardinal("en"), "1", 0},
+		{en, Cardinal("en"), "1.0", 1},
+		{en, Cardinal("en"), "1.5", 1},
+		{en, Cardinal("en"), "2", 1},
+		{en, nil, "1.5", 0},
+		{fr, Cardinal("fr"), "1.5", 0},
+		{fr, Cardinal("fr"), "2.5", 1},
+		{fr, Cardinal("fr"), "1c6", 1},
+		{ru, Cardinal("ru"), "1", 0},
+		{ru, Cardinal("ru"), "21", 0},
+		{ru, Cardinal("ru"), "5", 2},
+		{ru, Cardinal("ru"), "0.5", 1}, // "other" has the form of "few"
+		{ru, Cardinal("ru"), "1.5", 1},
+		{ru, Cardinal("ru"), "2.5", 1},
+		{pl, Cardinal("pl"), "1.5", 1},
+		{pl, Cardinal("pl"), "5", 2},
+		{cs, Cardinal("cs"), "1.5", 2}, // "many" has the form of "other"
+	} {
+		ops, err := ParseOperands(v.num)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x := DecimalIndex(v.formula, v.rules, ops); x != v.index {
+			t.Fatalf("%s: expect = %d, got = %d", v.num, v.index, x)
+		}
+	}
+}
+
+func TestOperands_String(t *testing.T) {
+	for _, s := range []string{"0", "1", "1.0", "1.05", "123.450"} {
+		ops, err := ParseOperands(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x := ops.String(); x != s {
+			t.Fatalf("expect = %s, got = %s", s, x)
+		}
+	}
+}
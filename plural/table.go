@@ -2,21 +2,30 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+// Code generated by gen.go; DO NOT EDIT.
+
 package plural
 
 // FormsTable are standard hard-coded plural rules.
 // The application developers and the translators need to understand them.
 //
+// The languages of GNU's gettext table come first, the other languages
+// use a formula derived from the CLDR integer rules.
+//
 // See GNU's gettext library source code: gettext/gettext-tools/src/plural-table.c
+// See CLDR 42: common/supplemental/plurals.xml
 var FormsTable = []struct {
 	Lang     string
 	Language string
 	Value    string
 }{
 	{"??", "Unknown", "nplurals=1; plural=0;"},
+
+	// GNU gettext: gettext-tools/src/plural-table.c
 	{"ja", "Japanese", "nplurals=1; plural=0;"},
 	{"vi", "Vietnamese", "nplurals=1; plural=0;"},
 	{"ko", "Korean", "nplurals=1; plural=0;"},
+	{"zh", "Chinese", "nplurals=1; plural=0;"},
 	{"en", "English", "nplurals=2; plural=(n != 1);"},
 	{"de", "German", "nplurals=2; plural=(n != 1);"},
 	{"nl", "Dutch", "nplurals=2; plural=(n != 1);"},
@@ -52,4 +61,179 @@ var FormsTable = []struct {
 	{"sk", "Slovak", "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;"},
 	{"pl", "Polish", "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"},
 	{"sl", "Slovenian", "nplurals=4; plural=(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3);"},
+	{"ar", "Arabic", "nplurals=6; plural=n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5;"},
+
+	// CLDR 42: common/supplemental/plurals.xml
+	{"af", "Afrikaans", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ak", "Akan", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"am", "Amharic", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"an", "Aragonese", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ars", "Najdi Arabic", "nplurals=6; plural=n == 0 ? 0 : n == 1 ? 1 : n == 2 ? 2 : n % 100 >= 3 && n % 100 <= 10 ? 3 : n % 100 >= 11 && n % 100 <= 99 ? 4 : 5;"},
+	{"as", "Assamese", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"asa", "Asu", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ast", "Asturian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"az", "Azerbaijani", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"bal", "Baluchi", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"bem", "Bemba", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"bez", "Bena", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"bho", "Bhojpuri", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"bm", "Bambara", "nplurals=1; plural=0;"},
+	{"bn", "Bengali", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"bo", "Tibetan", "nplurals=1; plural=0;"},
+	{"br", "Breton", "nplurals=5; plural=n % 10 == 1 && n % 100 != 11 && n % 100 != 71 && n % 100 != 91 ? 0 : n % 10 == 2 && n % 100 != 12 && n % 100 != 72 && n % 100 != 92 ? 1 : (n % 10 >= 3 && n % 10 <= 4 || n % 10 == 9) && (n % 100 < 10 || n % 100 > 19) && (n % 100 < 70 || n % 100 > 79) && (n % 100 < 90 || n % 100 > 99) ? 2 : n != 0 && n % 1000000 == 0 ? 3 : 4;"},
+	{"brx", "Bodo", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"bs", "Bosnian", "nplurals=3; plural=n % 10 == 1 && n % 100 != 11 ? 0 : n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14) ? 1 : 2;"},
+	{"ca", "Catalan", "nplurals=3; plural=n == 1 ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2;"},
+	{"ce", "Chechen", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ceb", "Cebuano", "nplurals=2; plural=((n == 1 || n == 2 || n == 3) || (n % 10 != 4 && n % 10 != 6 && n % 10 != 9) ? 0 : 1);"},
+	{"cgg", "Chiga", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"chr", "Cherokee", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ckb", "Central Kurdish", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"cy", "Welsh", "nplurals=6; plural=n == 0 ? 0 : n == 1 ? 1 : n == 2 ? 2 : n == 3 ? 3 : n == 6 ? 4 : 5;"},
+	{"doi", "Dogri", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"dsb", "Lower Sorbian", "nplurals=4; plural=n % 100 == 1 ? 0 : n % 100 == 2 ? 1 : n % 100 >= 3 && n % 100 <= 4 ? 2 : 3;"},
+	{"dv", "Divehi", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"dz", "Dzongkha", "nplurals=1; plural=0;"},
+	{"ee", "Ewe", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"eu", "Basque", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"fa", "Persian", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"ff", "Fulah", "nplurals=2; plural=((n == 0 || n == 1) ? 0 : 1);"},
+	{"fil", "Filipino", "nplurals=2; plural=((n == 1 || n == 2 || n == 3) || (n % 10 != 4 && n % 10 != 6 && n % 10 != 9) ? 0 : 1);"},
+	{"fur", "Friulian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"fy", "Western Frisian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"gd", "Scottish Gaelic", "nplurals=4; plural=(n == 1 || n == 11) ? 0 : (n == 2 || n == 12) ? 1 : (n >= 3 && n <= 10 || n >= 13 && n <= 19) ? 2 : 3;"},
+	{"gl", "Galician", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"gsw", "Swiss German", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"gu", "Gujarati", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"guw", "Gun", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"gv", "Manx", "nplurals=4; plural=n % 10 == 1 ? 0 : n % 10 == 2 ? 1 : (n % 100 == 0 || n % 100 == 20 || n % 100 == 40 || n % 100 == 60 || n % 100 == 80) ? 2 : 3;"},
+	{"ha", "Hausa", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"haw", "Hawaiian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"hi", "Hindi", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"hnj", "Hmong Njua", "nplurals=1; plural=0;"},
+	{"hsb", "Upper Sorbian", "nplurals=4; plural=n % 100 == 1 ? 0 : n % 100 == 2 ? 1 : n % 100 >= 3 && n % 100 <= 4 ? 2 : 3;"},
+	{"hy", "Armenian", "nplurals=2; plural=((n == 0 || n == 1) ? 0 : 1);"},
+	{"ia", "Interlingua", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"id", "Indonesian", "nplurals=1; plural=0;"},
+	{"ig", "Igbo", "nplurals=1; plural=0;"},
+	{"ii", "Sichuan Yi", "nplurals=1; plural=0;"},
+	{"io", "Ido", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"is", "Icelandic", "nplurals=2; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : 1);"},
+	{"iu", "Inuktitut", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"jbo", "Lojban", "nplurals=1; plural=0;"},
+	{"jgo", "Ngomba", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"jmc", "Machame", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"jv", "Javanese", "nplurals=1; plural=0;"},
+	{"ka", "Georgian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"kab", "Kabyle", "nplurals=2; plural=((n == 0 || n == 1) ? 0 : 1);"},
+	{"kaj", "Jju", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"kcg", "Tyap", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"kde", "Makonde", "nplurals=1; plural=0;"},
+	{"kea", "Kabuverdianu", "nplurals=1; plural=0;"},
+	{"kk", "Kazakh", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"kkj", "Kako", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"kl", "Kalaallisut", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"km", "Central Khmer", "nplurals=1; plural=0;"},
+	{"kn", "Kannada", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"ks", "Kashmiri", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ksb", "Shambala", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ksh", "Colognian", "nplurals=3; plural=n == 0 ? 0 : n == 1 ? 1 : 2;"},
+	{"ku", "Kurdish", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"kw", "Cornish", "nplurals=6; plural=n == 0 ? 0 : n == 1 ? 1 : (n % 100 == 2 || n % 100 == 22 || n % 100 == 42 || n % 100 == 62 || n % 100 == 82) || (n % 1000 == 0 && (n % 100000 >= 1000 && n % 100000 <= 20000 || n % 100000 == 40000 || n % 100000 == 60000 || n % 100000 == 80000)) || (n != 0 && n % 1000000 == 100000) ? 2 : (n % 100 == 3 || n % 100 == 23 || n % 100 == 43 || n % 100 == 63 || n % 100 == 83) ? 3 : n != 1 && (n % 100 == 1 || n % 100 == 21 || n % 100 == 41 || n % 100 == 61 || n % 100 == 81) ? 4 : 5;"},
+	{"ky", "Kirghiz", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"lag", "Langi", "nplurals=3; plural=n == 0 ? 0 : (n == 0 || n == 1) && n != 0 ? 1 : 2;"},
+	{"lb", "Luxembourgish", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"lg", "Ganda", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"lij", "Ligurian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"lkt", "Lakota", "nplurals=1; plural=0;"},
+	{"ln", "Lingala", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"lo", "Lao", "nplurals=1; plural=0;"},
+	{"mas", "Masai", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"mg", "Malagasy", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"mgo", "Meta'", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"mk", "Macedonian", "nplurals=2; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : 1);"},
+	{"ml", "Malayalam", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"mn", "Mongolian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"mr", "Marathi", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ms", "Malay", "nplurals=1; plural=0;"},
+	{"mt", "Maltese", "nplurals=5; plural=n == 1 ? 0 : n == 2 ? 1 : n == 0 || (n % 100 >= 3 && n % 100 <= 10) ? 2 : n % 100 >= 11 && n % 100 <= 19 ? 3 : 4;"},
+	{"my", "Burmese", "nplurals=1; plural=0;"},
+	{"nah", "Nahuatl", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"naq", "Nama", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"nd", "North Ndebele", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ne", "Nepali", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"nnh", "Ngiemboon", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"nqo", "N'Ko", "nplurals=1; plural=0;"},
+	{"nr", "South Ndebele", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"nso", "Northern Sotho", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"ny", "Nyanja", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"nyn", "Nyankole", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"om", "Oromo", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"or", "Oriya", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"os", "Ossetian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"osa", "Osage", "nplurals=1; plural=0;"},
+	{"pa", "Punjabi", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"pap", "Papiamento", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"pcm", "Nigerian Pidgin", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
+	{"prg", "Prussian", "nplurals=3; plural=n % 10 == 0 || (n % 100 >= 11 && n % 100 <= 19) ? 0 : n % 10 == 1 && n % 100 != 11 ? 1 : 2;"},
+	{"ps", "Pashto", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"rm", "Romansh", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"rof", "Rombo", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"rwk", "Rwa", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"sah", "Yakut", "nplurals=1; plural=0;"},
+	{"saq", "Samburu", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"sat", "Santali", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"sc", "Sardinian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"scn", "Sicilian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"sd", "Sindhi", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"sdh", "Southern Kurdish", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"se", "Northern Sami", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"seh", "Sena", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ses", "Koyraboro Senni", "nplurals=1; plural=0;"},
+	{"sg", "Sango", "nplurals=1; plural=0;"},
+	{"shi", "Tachelhit", "nplurals=3; plural=n == 0 || n == 1 ? 0 : n >= 2 && n <= 10 ? 1 : 2;"},
+	{"si", "Sinhala", "nplurals=2; plural=((n == 0 || n == 1) ? 0 : 1);"},
+	{"sma", "Southern Sami", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"smi", "Sami", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"smj", "Lule Sami", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"smn", "Inari Sami", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"sms", "Skolt Sami", "nplurals=3; plural=n == 1 ? 0 : n == 2 ? 1 : 2;"},
+	{"sn", "Shona", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"so", "Somali", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"sq", "Albanian", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ss", "Swati", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ssy", "Saho", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"st", "Southern Sotho", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"su", "Sundanese", "nplurals=1; plural=0;"},
+	{"sw", "Swahili", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"syr", "Syriac", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ta", "Tamil", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"te", "Telugu", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"teo", "Teso", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"th", "Thai", "nplurals=1; plural=0;"},
+	{"ti", "Tigrinya", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"tig", "Tigre", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"tk", "Turkmen", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"tl", "Tagalog", "nplurals=2; plural=((n == 1 || n == 2 || n == 3) || (n % 10 != 4 && n % 10 != 6 && n % 10 != 9) ? 0 : 1);"},
+	{"tn", "Tswana", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"to", "Tonga", "nplurals=1; plural=0;"},
+	{"tpi", "Tok Pisin", "nplurals=1; plural=0;"},
+	{"ts", "Tsonga", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"tzm", "Central Atlas Tamazight", "nplurals=2; plural=((n >= 0 && n <= 1) || (n >= 11 && n <= 99) ? 0 : 1);"},
+	{"ug", "Uighur", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ur", "Urdu", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"uz", "Uzbek", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"ve", "Venda", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"vec", "Venetian", "nplurals=3; plural=n == 1 ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2;"},
+	{"vo", "Volapuk", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"vun", "Vunjo", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"wa", "Walloon", "nplurals=2; plural=(n >= 0 && n <= 1 ? 0 : 1);"},
+	{"wae", "Walser", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"wo", "Wolof", "nplurals=1; plural=0;"},
+	{"xh", "Xhosa", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"xog", "Soga", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"yi", "Yiddish", "nplurals=2; plural=(n == 1 ? 0 : 1);"},
+	{"yo", "Yoruba", "nplurals=1; plural=0;"},
+	{"yue", "Cantonese", "nplurals=1; plural=0;"},
+	{"zu", "Zulu", "nplurals=2; plural=(n == 0 || n == 1 ? 0 : 1);"},
 }
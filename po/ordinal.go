@@ -0,0 +1,69 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package po
+
+import (
+	"strings"
+)
+
+// OrdinalContext is the msgctxt of the ordinal messages.
+//
+// GNU's gettext has no ordinal messages, so they are stored as plural
+// messages with a reserved msgctxt, which survive the PO and MO formats:
+//
+//	#, ordinal
+//	msgctxt "ordinal"
+//	msgid "%d"
+//	msgid_plural "%d"
+//	msgstr[0] "%dst"
+//	msgstr[1] "%dnd"
+//	msgstr[2] "%drd"
+//	msgstr[3] "%dth"
+//
+// The msgctxt is "ordinal", or "ordinal|context" if the message has
+// a context. The msgid_plural is the same as msgid. There is one msgstr[i]
+// for every CLDR ordinal category of the language, in the order of
+// plural.Ordinal(lang).Categories ("one", "two", "few", "other" for "en").
+const OrdinalContext = "ordinal"
+
+// OrdinalMsgContext returns the msgctxt of the ordinal message with
+// the msgctxt context.
+func OrdinalMsgContext(msgctxt string) string {
+	if msgctxt == "" {
+		return OrdinalContext
+	}
+	return OrdinalContext + "|" + msgctxt
+}
+
+// NewOrdinalMessage returns an ordinal message, msgstr are the
+// translations of the CLDR ordinal categories of the language.
+func NewOrdinalMessage(msgctxt, msgid string, msgstr []string) Message {
+	msg := Message{
+		MsgContext:   OrdinalMsgContext(msgctxt),
+		MsgId:        msgid,
+		MsgIdPlural:  msgid,
+		MsgStrPlural: append([]string(nil), msgstr...),
+	}
+	msg.Flags = append(msg.Flags, OrdinalContext)
+	return msg
+}
+
+// IsOrdinal reports whether the message is an ordinal message.
+func (p *Message) IsOrdinal() bool {
+	_, ok := p.OrdinalContext()
+	return ok
+}
+
+// OrdinalContext returns the context of an ordinal message,
+// ok is false if the message is not an ordinal message.
+func (p *Message) OrdinalContext() (msgctxt string, ok bool) {
+	if p.MsgContext == OrdinalContext {
+		return "", true
+	}
+	if strings.HasPrefix(p.MsgContext, OrdinalContext+"|") {
+		return p.MsgContext[len(OrdinalContext)+1:], true
+	}
+	return "", false
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package po
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrdinalMessage(t *testing.T) {
+	var f File
+	f.MimeHeader.Language = "en"
+	f.Messages = append(f.Messages,
+		NewOrdinalMessage("", "%d", []string{"%dst", "%dnd", "%drd", "%dth"}),
+		NewOrdinalMessage("race", "%d place", []string{"%dst place", "%dnd place", "%drd place", "%dth place"}),
+		Message{MsgContext: "ordinals", MsgId: "Ordinals", MsgStr: "Ordinals"},
+	)
+
+	g, err := Load(f.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Messages) != len(f.Messages) {
+		t.Fatalf("expect = %d, got = %d", len(f.Messages), len(g.Messages))
+	}
+
+	// Data sorts the messages
+	find := func(msgid string) *Message {
+		for i := range g.Messages {
+			if g.Messages[i].MsgId == msgid {
+				return &g.Messages[i]
+			}
+		}
+		t.Fatalf("%q: missing", msgid)
+		return nil
+	}
+
+	for i, ctx := range []string{"", "race"} {
+		msg := find(f.Messages[i].MsgId)
+		if s, ok := msg.OrdinalContext(); !ok || s != ctx {
+			t.Fatalf("%d: expect = %q, got = %q, %v", i, ctx, s, ok)
+		}
+		if msg.MsgIdPlural != msg.MsgId {
+			t.Fatalf("%d: expect = %q, got = %q", i, msg.MsgId, msg.MsgIdPlural)
+		}
+		if !reflect.DeepEqual(msg.MsgStrPlural, f.Messages[i].MsgStrPlural) {
+			t.Fatalf("%d: expect = %q, got = %q", i, f.Messages[i].MsgStrPlural, msg.MsgStrPlural)
+		}
+		if !reflect.DeepEqual(msg.Flags, []string{"ordinal"}) {
+			t.Fatalf("%d: flags: got = %q", i, msg.Flags)
+		}
+	}
+	if find("Ordinals").IsOrdinal() {
+		t.Fatal("expect not ordinal")
+	}
+}
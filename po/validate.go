@@ -0,0 +1,93 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package po
+
+import (
+	"fmt"
+
+	"github.com/chai2010/gettext-go/plural"
+)
+
+// MaxCheckedPluralN is the largest n checked by Validate
+// against the Plural-Forms expression.
+const MaxCheckedPluralN = 1000
+
+// Diagnostic is a problem reported by File.Validate.
+type Diagnostic struct {
+	Line       int    // start line of the entry, see Comment.StartLine
+	MsgContext string // msgctxt of the entry, empty for the header
+	MsgId      string // msgid of the entry, empty for the header
+	Message    string // description of the problem
+}
+
+// String returns the diagnostic as "line N: message".
+func (d Diagnostic) String() string {
+	if d.MsgId == "" {
+		return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+	}
+	return fmt.Sprintf("line %d: %q: %s", d.Line, d.MsgId, d.Message)
+}
+
+// Validate checks the Plural-Forms header and the plural entries.
+//
+// It reports a missing or invalid Plural-Forms header (if the file has
+// plural entries), a plural expression which evaluates out of the
+// [0, nplurals) range for any n in 0..MaxCheckedPluralN, and the plural
+// entries which don't have exactly nplurals msgstr[n] values.
+//
+// An empty result means the file is valid.
+func (f *File) Validate() []Diagnostic {
+	var ds []Diagnostic
+	header := func(format string, a ...interface{}) {
+		ds = append(ds, Diagnostic{
+			Line:    f.MimeHeader.StartLine,
+			Message: fmt.Sprintf(format, a...),
+		})
+	}
+
+	var hasPlural bool
+	for i := 0; i < len(f.Messages); i++ {
+		if f.Messages[i].MsgIdPlural != "" {
+			hasPlural = true
+			break
+		}
+	}
+
+	if f.MimeHeader.PluralForms == "" {
+		if hasPlural {
+			header("missing Plural-Forms header")
+		}
+		return ds
+	}
+	forms, err := plural.ParseForms(f.MimeHeader.PluralForms)
+	if err != nil {
+		header("invalid Plural-Forms header: %v", err)
+		return ds
+	}
+	for n := 0; n <= MaxCheckedPluralN; n++ {
+		if x := forms.Eval(n); x < 0 || x >= forms.NPlurals {
+			header("plural expression %q evaluates to %d for n = %d, nplurals = %d",
+				forms.Plural, x, n, forms.NPlurals,
+			)
+			break
+		}
+	}
+
+	for i := 0; i < len(f.Messages); i++ {
+		msg := &f.Messages[i]
+		if msg.MsgIdPlural == "" || msg.IsOrdinal() {
+			continue
+		}
+		if n := len(msg.MsgStrPlural); n != forms.NPlurals {
+			ds = append(ds, Diagnostic{
+				Line:       msg.StartLine,
+				MsgContext: msg.MsgContext,
+				MsgId:      msg.MsgId,
+				Message:    fmt.Sprintf("has %d plural forms, nplurals = %d", n, forms.NPlurals),
+			})
+		}
+	}
+	return ds
+}
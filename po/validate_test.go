@@ -0,0 +1,74 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package po
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFile_Validate(t *testing.T) {
+	f, err := Load([]byte(testValidatePoData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := f.Validate()
+	if len(ds) != 1 {
+		t.Fatalf("expect 1 diagnostic, got = %v", ds)
+	}
+	if d := ds[0]; d.Line != 14 || d.MsgId != "%d dog" || !strings.Contains(d.Message, "has 2 plural forms") {
+		t.Fatalf("got = %v", d)
+	}
+	if s := ds[0].String(); s != `line 14: "%d dog": has 2 plural forms, nplurals = 3` {
+		t.Fatalf("got = %s", s)
+	}
+}
+
+func TestFile_Validate_header(t *testing.T) {
+	for _, v := range []struct {
+		forms   string
+		message string
+	}{
+		{"", "missing Plural-Forms header"},
+		{"nplurals=3; plural=(n != 1;", "invalid Plural-Forms header"},
+		{"nplurals=3; plural=n;", "evaluates to 3 for n = 3"},
+	} {
+		f, err := Load([]byte(testValidatePoData))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.MimeHeader.PluralForms = v.forms
+
+		ds := f.Validate()
+		if len(ds) == 0 {
+			t.Fatalf("%q: expect diagnostics", v.forms)
+		}
+		if d := ds[0]; d.Line != 2 || d.MsgId != "" || !strings.Contains(d.Message, v.message) {
+			t.Fatalf("%q: got = %v", v.forms, d)
+		}
+	}
+}
+
+const testValidatePoData = `
+# header
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%d cat"
+msgid_plural "%d cats"
+msgstr[0] "%d кошка"
+msgstr[1] "%d кошки"
+msgstr[2] "%d кошек"
+
+msgid "%d dog"
+msgid_plural "%d dogs"
+msgstr[0] "%d собака"
+msgstr[1] "%d собаки"
+
+msgid "Hello"
+msgstr "Привет"
+`
@@ -35,7 +35,7 @@ func TestPoMoFiles(t *testing.T) {
 	for i := 0; i < len(testPoMoFiles); i++ {
 		poName := testPoMoFiles[i].poFile
 		moName := testPoMoFiles[i].moFile
-		po, err := newPoTranslator(testDataDir+poName, nil)
+		po, err := newPoTranslator(testDataDir+poName, nil, false)
 		if err != nil {
 			t.Fatalf("%s: %v", poName, err)
 		}
@@ -5,7 +5,7 @@
 package gettext
 
 import (
-	"encoding/json"
+	"strings"
 
 	"github.com/chai2010/gettext-go/mo"
 	"github.com/chai2010/gettext-go/plural"
@@ -13,13 +13,19 @@ import (
 )
 
 var nilTranslator = &translator{
-	MessageMap:    make(map[string]mo.Message),
-	PluralFormula: plural.Formula("??"),
+	MessageMap:     make(map[string]mo.Message),
+	PluralFormula:  plural.Formula("??"),
+	OrdinalFormula: plural.Ordinal("??").Formula(),
+	DecimalFormula: plural.DecimalFormula(plural.Formula("??"), nil),
 }
 
 type translator struct {
-	MessageMap    map[string]mo.Message
-	PluralFormula func(n int) int
+	MessageMap     map[string]mo.Message
+	PluralFormula  func(n int) int
+	OrdinalFormula func(n int) int
+	CardinalRules  *plural.Rules               // CLDR rules for the decimal numbers, nil if unknown
+	DecimalFormula func(n plural.Operands) int // CardinalRules mapped to PluralFormula
+	Diagnostics    []po.Diagnostic             // Plural-Forms problems, see po.File.Validate
 }
 
 func newMoTranslator(name string, data []byte) (*translator, error) {
@@ -35,21 +41,27 @@ func newMoTranslator(name string, data []byte) (*translator, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newMoFileTranslator(f), nil
+}
+
+func newMoFileTranslator(f *mo.File) *translator {
 	var tr = &translator{
 		MessageMap: make(map[string]mo.Message),
 	}
+	invalid := tr.validate(moToPoFile(f))
 	for _, v := range f.Messages {
+		if invalid[tr.makeMapKey(v.MsgContext, v.MsgId)] {
+			continue
+		}
 		tr.MessageMap[tr.makeMapKey(v.MsgContext, v.MsgId)] = v
 	}
-	if lang := f.MimeHeader.Language; lang != "" {
-		tr.PluralFormula = plural.Formula(lang)
-	} else {
-		tr.PluralFormula = plural.Formula("??")
-	}
-	return tr, nil
+	tr.setLanguage(f.MimeHeader.Language)
+	return tr
 }
 
-func newPoTranslator(name string, data []byte) (*translator, error) {
+// newPoTranslator loads the po file, the fuzzy messages are skipped
+// unless useFuzzy is true (like msgfmt --use-fuzzy).
+func newPoTranslator(name string, data []byte, useFuzzy bool) (*translator, error) {
 	var (
 		f   *po.File
 		err error
@@ -62,10 +74,21 @@ func newPoTranslator(name string, data []byte) (*translator, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newPoFileTranslator(f, useFuzzy), nil
+}
+
+func newPoFileTranslator(f *po.File, useFuzzy bool) *translator {
 	var tr = &translator{
 		MessageMap: make(map[string]mo.Message),
 	}
+	invalid := tr.validate(f)
 	for _, v := range f.Messages {
+		if invalid[tr.makeMapKey(v.MsgContext, v.MsgId)] {
+			continue
+		}
+		if v.GetFuzzy() && !useFuzzy {
+			continue
+		}
 		tr.MessageMap[tr.makeMapKey(v.MsgContext, v.MsgId)] = mo.Message{
 			MsgContext:   v.MsgContext,
 			MsgId:        v.MsgId,
@@ -74,75 +97,238 @@ func newPoTranslator(name string, data []byte) (*translator, error) {
 			MsgStrPlural: v.MsgStrPlural,
 		}
 	}
-	if lang := f.MimeHeader.Language; lang != "" {
-		tr.PluralFormula = plural.Formula(lang)
-	} else {
-		tr.PluralFormula = plural.Formula("??")
+	tr.setLanguage(f.MimeHeader.Language)
+	return tr
+}
+
+// setLanguage sets the CLDR rules of the catalog's language,
+// the PluralFormula must be set.
+func (p *translator) setLanguage(lang string) {
+	p.OrdinalFormula = plural.Ordinal(lang).Formula()
+	if rules := plural.Cardinal(lang); rules.Locale != "root" {
+		p.CardinalRules = rules
 	}
-	return tr, nil
+	p.DecimalFormula = plural.DecimalFormula(p.PluralFormula, p.CardinalRules)
 }
 
-func newJsonTranslator(lang, name string, jsonData []byte) (*translator, error) {
-	var msgList []struct {
-		MsgContext  string   `json:"msgctxt"`      // msgctxt context
-		MsgId       string   `json:"msgid"`        // msgid untranslated-string
-		MsgIdPlural string   `json:"msgid_plural"` // msgid_plural untranslated-string-plural
-		MsgStr      []string `json:"msgstr"`       // msgstr translated-string
+// validate checks the Plural-Forms header and the plural messages,
+// sets the plural formula and returns the keys of the invalid messages.
+//
+// If the header is missing or invalid, the language's standard formula
+// is used. The plural messages with a wrong number of forms should be
+// dropped (like msgfmt rejects them), so they fall back to msgid/msgid_plural
+// instead of returning a wrong form.
+func (p *translator) validate(f *po.File) (invalid map[string]bool) {
+	p.Diagnostics = f.Validate()
+	p.PluralFormula = plural.FormsFormula(f.MimeHeader.PluralForms, f.MimeHeader.Language)
+
+	invalid = make(map[string]bool)
+	for _, d := range p.Diagnostics {
+		if d.MsgId == "" {
+			p.PluralFormula = plural.FormsFormula("", f.MimeHeader.Language)
+		} else {
+			invalid[p.makeMapKey(d.MsgContext, d.MsgId)] = true
+		}
+	}
+	return invalid
+}
+
+// moToPoFile returns the po.File of the mo file's header and messages,
+// without the comments.
+func moToPoFile(f *mo.File) *po.File {
+	var file = &po.File{
+		MimeHeader: po.Header{
+			ProjectIdVersion:        f.MimeHeader.ProjectIdVersion,
+			ReportMsgidBugsTo:       f.MimeHeader.ReportMsgidBugsTo,
+			POTCreationDate:         f.MimeHeader.POTCreationDate,
+			PORevisionDate:          f.MimeHeader.PORevisionDate,
+			LastTranslator:          f.MimeHeader.LastTranslator,
+			LanguageTeam:            f.MimeHeader.LanguageTeam,
+			Language:                f.MimeHeader.Language,
+			MimeVersion:             f.MimeHeader.MimeVersion,
+			ContentType:             f.MimeHeader.ContentType,
+			ContentTransferEncoding: f.MimeHeader.ContentTransferEncoding,
+			PluralForms:             f.MimeHeader.PluralForms,
+			XGenerator:              f.MimeHeader.XGenerator,
+			UnknowFields:            f.MimeHeader.UnknowFields,
+		},
+	}
+	for _, v := range f.Messages {
+		file.Messages = append(file.Messages, po.Message{
+			MsgContext:   v.MsgContext,
+			MsgId:        v.MsgId,
+			MsgIdPlural:  v.MsgIdPlural,
+			MsgStr:       v.MsgStr,
+			MsgStrPlural: v.MsgStrPlural,
+		})
 	}
-	if err := json.Unmarshal(jsonData, &msgList); err != nil {
+	return file
+}
+
+func newJsonTranslator(lang, name string, jsonData []byte) (*translator, error) {
+	f, err := loadJsonFile(lang, jsonData)
+	if err != nil {
 		return nil, err
 	}
+	return newJsonFileTranslator(f), nil
+}
 
-	var tr = &translator{
-		MessageMap:    make(map[string]mo.Message),
-		PluralFormula: plural.Formula(lang),
+// loadJsonFile returns the po.File of the json messages, the header
+// entry (msgid "") is the MimeHeader, see parseJsonHeader.
+func loadJsonFile(lang string, jsonData []byte) (*po.File, error) {
+	var msgList []jsonMessage
+	if err := unmarshalJsonStrict(jsonData, &msgList); err != nil {
+		return nil, err
 	}
 
+	var f = &po.File{MimeHeader: po.Header{Language: lang}}
 	for _, v := range msgList {
-		var v_MsgStr string
-		var v_MsgStrPlural = v.MsgStr
-
+		if v.MsgContext == "" && v.MsgId == "" {
+			if len(v.MsgStr) != 0 {
+				f.MimeHeader = parseJsonHeader(v.MsgStr[0], lang)
+			}
+			continue
+		}
+		var msg = po.Message{
+			MsgContext:   v.MsgContext,
+			MsgId:        v.MsgId,
+			MsgIdPlural:  v.MsgIdPlural,
+			MsgStrPlural: v.MsgStr,
+		}
 		if len(v.MsgStr) != 0 {
-			v_MsgStr = v.MsgStr[0]
+			msg.MsgStr = v.MsgStr[0]
 		}
+		f.Messages = append(f.Messages, msg)
+	}
+	return f, nil
+}
 
+// newJsonFileTranslator returns the translator of the json messages,
+// the plural formula is the header's Plural-Forms, or the standard
+// one of the language.
+func newJsonFileTranslator(f *po.File) *translator {
+	var tr = &translator{
+		MessageMap:    make(map[string]mo.Message),
+		PluralFormula: plural.Formula(f.MimeHeader.Language),
+	}
+	var invalid map[string]bool
+	if f.MimeHeader.PluralForms != "" {
+		invalid = tr.validate(f)
+	}
+	for _, v := range f.Messages {
+		if invalid[tr.makeMapKey(v.MsgContext, v.MsgId)] {
+			continue
+		}
 		tr.MessageMap[tr.makeMapKey(v.MsgContext, v.MsgId)] = mo.Message{
 			MsgContext:   v.MsgContext,
 			MsgId:        v.MsgId,
 			MsgIdPlural:  v.MsgIdPlural,
-			MsgStr:       v_MsgStr,
-			MsgStrPlural: v_MsgStrPlural,
+			MsgStr:       v.MsgStr,
+			MsgStrPlural: v.MsgStrPlural,
 		}
 	}
-	return tr, nil
+	tr.setLanguage(f.MimeHeader.Language)
+	return tr
+}
+
+// parseJsonHeader returns the Language and Plural-Forms fields of the
+// json header entry, "Language: ja\nPlural-Forms: nplurals=1; plural=0;\n".
+// The Language is lang if it's missing.
+func parseJsonHeader(s, lang string) po.Header {
+	var h = po.Header{Language: lang}
+	for _, line := range strings.Split(s, "\n") {
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			continue
+		}
+		key, val := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+		switch {
+		case strings.EqualFold(key, "Language") && val != "":
+			h.Language = val
+		case strings.EqualFold(key, "Plural-Forms"):
+			h.PluralForms = val
+		}
+	}
+	return h
 }
 
 func (p *translator) PGettext(msgctxt, msgid string) string {
-	return p.findMsgStr(msgctxt, msgid)
+	if s, ok := p.pGettext(msgctxt, msgid); ok {
+		return s
+	}
+	return msgid
 }
 
 func (p *translator) PNGettext(msgctxt, msgid, msgidPlural string, n int) string {
-	n = p.PluralFormula(n)
+	if s, ok := p.pnGettext(msgctxt, msgid, msgidPlural, p.PluralFormula(n)); ok {
+		return s
+	}
+	return untranslated(msgid, msgidPlural, n == 1)
+}
+
+// PNGettextDecimal is like PNGettext, but for a decimal number,
+// see plural.DecimalIndex.
+func (p *translator) PNGettextDecimal(msgctxt, msgid, msgidPlural string, n plural.Operands) string {
+	if s, ok := p.pnGettext(msgctxt, msgid, msgidPlural, p.decimalIndex(n)); ok {
+		return s
+	}
+	return untranslated(msgid, msgidPlural, n.I == 1 && n.IsInt())
+}
+
+// POGettext returns the ordinal form of n, see po.OrdinalContext.
+func (p *translator) POGettext(msgctxt, msgid string, n int) string {
+	if s, ok := p.poGettext(msgctxt, msgid, n); ok {
+		return s
+	}
+	return msgid
+}
+
+func (p *translator) decimalIndex(n plural.Operands) int {
+	return p.DecimalFormula(n)
+}
+
+// pGettext returns the translation of msgid, ok is false if it's untranslated.
+func (p *translator) pGettext(msgctxt, msgid string) (msgstr string, ok bool) {
+	if v, ok := p.MessageMap[p.makeMapKey(msgctxt, msgid)]; ok && v.MsgStr != "" {
+		return v.MsgStr, true
+	}
+	return "", false
+}
+
+// pnGettext returns the i-th plural form, ok is false if it's untranslated.
+func (p *translator) pnGettext(msgctxt, msgid, msgidPlural string, i int) (msgstr string, ok bool) {
 	if ss := p.findMsgStrPlural(msgctxt, msgid, msgidPlural); len(ss) != 0 {
-		if n >= len(ss) {
-			n = len(ss) - 1
+		if i >= len(ss) {
+			i = len(ss) - 1
 		}
-		if ss[n] != "" {
-			return ss[n]
+		if i >= 0 && ss[i] != "" {
+			return ss[i], true
 		}
 	}
-	if msgidPlural != "" && n > 0 {
-		return msgidPlural
+	return "", false
+}
+
+// poGettext returns the ordinal form of n, ok is false if it's untranslated.
+func (p *translator) poGettext(msgctxt, msgid string, n int) (msgstr string, ok bool) {
+	ss := p.findMsgStrPlural(po.OrdinalMsgContext(msgctxt), msgid, msgid)
+	if len(ss) == 0 {
+		return "", false
 	}
-	return msgid
+	n = p.OrdinalFormula(n)
+	if n >= len(ss) {
+		n = len(ss) - 1
+	}
+	if ss[n] != "" {
+		return ss[n], true
+	}
+	return "", false
 }
 
-func (p *translator) findMsgStr(msgctxt, msgid string) string {
-	key := p.makeMapKey(msgctxt, msgid)
-	if v, ok := p.MessageMap[key]; ok {
-		if v.MsgStr != "" {
-			return v.MsgStr
-		}
+// untranslated returns msgid or msgidPlural of an untranslated message,
+// using the source language's (English) rule as GNU does.
+func untranslated(msgid, msgidPlural string, isOne bool) string {
+	if msgidPlural != "" && !isOne {
+		return msgidPlural
 	}
 	return msgid
 }
@@ -173,3 +359,44 @@ func (p *translator) makeMapKey(msgctxt, msgid string) string {
 	}
 	return msgid
 }
+
+// trChain is the translators of a language fallback chain,
+// a message missing in a translator is looked up in the next one.
+// The methods return false if the message is missing in all of them.
+type trChain []*translator
+
+func (c trChain) PGettext(msgctxt, msgid string) (string, bool) {
+	for _, tr := range c {
+		if s, ok := tr.pGettext(msgctxt, msgid); ok {
+			return s, true
+		}
+	}
+	return msgid, false
+}
+
+func (c trChain) PNGettext(msgctxt, msgid, msgidPlural string, n int) (string, bool) {
+	for _, tr := range c {
+		if s, ok := tr.pnGettext(msgctxt, msgid, msgidPlural, tr.PluralFormula(n)); ok {
+			return s, true
+		}
+	}
+	return untranslated(msgid, msgidPlural, n == 1), false
+}
+
+func (c trChain) PNGettextDecimal(msgctxt, msgid, msgidPlural string, n plural.Operands) (string, bool) {
+	for _, tr := range c {
+		if s, ok := tr.pnGettext(msgctxt, msgid, msgidPlural, tr.decimalIndex(n)); ok {
+			return s, true
+		}
+	}
+	return untranslated(msgid, msgidPlural, n.I == 1 && n.IsInt()), false
+}
+
+func (c trChain) POGettext(msgctxt, msgid string, n int) (string, bool) {
+	for _, tr := range c {
+		if s, ok := tr.poGettext(msgctxt, msgid, n); ok {
+			return s, true
+		}
+	}
+	return msgid, false
+}
@@ -5,14 +5,16 @@
 package gettext
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/chai2010/gettext-go/mo"
+	"github.com/chai2010/gettext-go/plural"
 	"github.com/chai2010/gettext-go/po"
 )
 
 func TestTranslator_Po(t *testing.T) {
-	tr, err := newPoTranslator("test", []byte(testTrPoData))
+	tr, err := newPoTranslator("test", []byte(testTrPoData), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -36,6 +38,137 @@ func TestTranslator_Mo(t *testing.T) {
 	}
 }
 
+func TestTranslator_PluralForms(t *testing.T) {
+	tr, err := newPoTranslator("test", []byte(testTrPluralPoData), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moTr, err := newMoTranslator("test", poToMoData(t, []byte(testTrPluralPoData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tr := range []*translator{tr, moTr} {
+		for n, expect := range []string{"0 zero", "1 one", "2 many", "3 many"} {
+			if out := tr.PNGettext("", "%d file", "%d files", n); out != expect[2:] {
+				t.Fatalf("%d: expect = %s, got = %s", n, expect[2:], out)
+			}
+		}
+	}
+}
+
+func TestTranslator_Ordinal(t *testing.T) {
+	tr, err := newPoTranslator("test", []byte(testTrOrdinalPoData), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moTr, err := newMoTranslator("test", poToMoData(t, []byte(testTrOrdinalPoData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tr := range []*translator{tr, moTr} {
+		for n, expect := range map[int]string{1: "%dst", 2: "%dnd", 3: "%drd", 4: "%dth", 11: "%dth", 22: "%dnd"} {
+			if out := tr.POGettext("", "%d", n); out != expect {
+				t.Fatalf("%d: expect = %s, got = %s", n, expect, out)
+			}
+		}
+		if out := tr.POGettext("race", "%d place", 3); out != "%drd place" {
+			t.Fatalf("expect = %s, got = %s", "%drd place", out)
+		}
+		if out := tr.POGettext("", "%d place", 3); out != "%d place" {
+			t.Fatalf("expect = %s, got = %s", "%d place", out)
+		}
+	}
+}
+
+func TestTranslator_Validate(t *testing.T) {
+	data := strings.Replace(testTrPluralPoData, `msgstr[2] "many"`, "", 1)
+	tr, err := newPoTranslator("test", []byte(data), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moTr, err := newMoTranslator("test", poToMoData(t, []byte(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tr := range []*translator{tr, moTr} {
+		tAssert(t, len(tr.Diagnostics) == 1, tr.Diagnostics)
+		// the entry has 2 forms, nplurals is 3
+		for n, expect := range []string{"%d files", "%d file", "%d files"} {
+			if out := tr.PNGettext("", "%d file", "%d files", n); out != expect {
+				t.Fatalf("%d: expect = %s, got = %s", n, expect, out)
+			}
+		}
+	}
+
+	// out of range expression, use the language's formula
+	data = strings.Replace(testTrPluralPoData, "n==1 ? 1 : 2", "n==1 ? 1 : 3", 1)
+	tr, err = newPoTranslator("test", []byte(data), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tAssert(t, len(tr.Diagnostics) == 1, tr.Diagnostics)
+	tAssert(t, tr.PluralFormula(5) == 0) // zh_CN
+}
+
+func TestTranslator_Decimal(t *testing.T) {
+	tr, err := newPoTranslator("test", []byte(testTrEnPoData), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		num    string
+		expect string
+	}{
+		{"1", "%s file(en)"},
+		{"1.0", "%s files(en)"},
+		{"1.5", "%s files(en)"},
+		{"18446744073709551615", "%s files(en)"},
+	} {
+		ops, err := plural.ParseOperands(v.num)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := tr.PNGettextDecimal("", "%s file", "%s files", ops); out != v.expect {
+			t.Fatalf("%s: expect = %s, got = %s", v.num, v.expect, out)
+		}
+	}
+
+	// the forms of a catalog without "other"
+	tr, err = newPoTranslator("test", []byte(testTrRuPoData), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		num    string
+		expect string
+	}{
+		{"1", "%s файл"},
+		{"2", "%s файла"},
+		{"5", "%s файлов"},
+		{"0.5", "%s файла"},
+		{"1.5", "%s файла"},
+		{"2.5", "%s файла"},
+	} {
+		ops, err := plural.ParseOperands(v.num)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := tr.PNGettextDecimal("", "%s file", "%s files", ops); out != v.expect {
+			t.Fatalf("%s: expect = %s, got = %s", v.num, v.expect, out)
+		}
+	}
+
+	// untranslated
+	ops, _ := plural.ParseOperands("1.0")
+	if out := nilTranslator.PNGettextDecimal("", "%s file", "%s files", ops); out != "%s files" {
+		t.Fatalf("expect = %s, got = %s", "%s files", out)
+	}
+	ops = plural.UintOperands(1)
+	if out := nilTranslator.PNGettextDecimal("", "%s file", "%s files", ops); out != "%s file" {
+		t.Fatalf("expect = %s, got = %s", "%s file", out)
+	}
+}
+
 func poToMoData(t *testing.T, data []byte) []byte {
 	poFile, err := po.Load(data)
 	if err != nil {
@@ -98,3 +231,93 @@ msgctxt "code.google.com/p/gettext-go/examples/hi.SayHi"
 msgid "pkg hi: Hello, world!"
 msgstr "来自\"Hi\"包的问候: 你好, 世界!"
 `
+
+var testTrPluralPoData = `
+msgid ""
+msgstr ""
+"Language: zh_CN\n"
+"Plural-Forms: nplurals=3; plural=n==0 ? 0 : n==1 ? 1 : 2;\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "zero"
+msgstr[1] "one"
+msgstr[2] "many"
+`
+
+var testTrOrdinalPoData = `
+msgid ""
+msgstr ""
+"Language: en\n"
+
+#, ordinal
+msgctxt "ordinal"
+msgid "%d"
+msgid_plural "%d"
+msgstr[0] "%dst"
+msgstr[1] "%dnd"
+msgstr[2] "%drd"
+msgstr[3] "%dth"
+
+#, ordinal
+msgctxt "ordinal|race"
+msgid "%d place"
+msgid_plural "%d place"
+msgstr[0] "%dst place"
+msgstr[1] "%dnd place"
+msgstr[2] "%drd place"
+msgstr[3] "%dth place"
+`
+
+var testTrEnPoData = `
+msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "%s file"
+msgid_plural "%s files"
+msgstr[0] "%s file(en)"
+msgstr[1] "%s files(en)"
+`
+
+func TestTranslator_Fuzzy(t *testing.T) {
+	data := `
+msgid "Open"
+msgstr "Öffnen"
+
+#, fuzzy, c-format
+msgid "Close %s"
+msgstr "Schließen %s"
+`
+	tr, err := newPoTranslator("test", []byte(data), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tAssert(t, tr.PGettext("", "Open") == "Öffnen")
+	tAssert(t, tr.PGettext("", "Close %s") == "Close %s")
+
+	tr, err = newPoTranslator("test", []byte(data), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tAssert(t, tr.PGettext("", "Close %s") == "Schließen %s")
+
+	g := New("hello", "", mapFS{"hello/de.po": data}).SetLanguage("de")
+	tAssert(t, g.Gettext("Close %s") == "Close %s")
+	g.SetUseFuzzy(true)
+	tAssert(t, g.Gettext("Close %s") == "Schließen %s")
+}
+
+var testTrRuPoData = `
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%s file"
+msgid_plural "%s files"
+msgstr[0] "%s файл"
+msgstr[1] "%s файла"
+msgstr[2] "%s файлов"
+`
@@ -10,13 +10,50 @@ import (
 )
 
 func getDefaultLanguage() string {
-	if v := os.Getenv("LC_MESSAGES"); v != "" {
-		return simplifiedLanguage(v)
+	return getDefaultLanguages()[0]
+}
+
+// getDefaultLanguages returns the user's preferred languages, as GNU's
+// gettext selects them for LC_MESSAGES:
+//
+// The locale is $(LC_ALL) or $(LC_MESSAGES) or $(LANG). If the locale
+// is not "C" or "POSIX", the $(LANGUAGE) priority list ("de_AT:de:en")
+// overrides it. The result is never empty, it's ["default"] if no
+// language is set.
+func getDefaultLanguages() []string {
+	return parseLanguages(os.Getenv)
+}
+
+func parseLanguages(getenv func(key string) string) []string {
+	return parseLocales(getenv, simplifiedLanguage)
+}
+
+// parseLocales is like parseLanguages, the names are simplified
+// by the simplify func.
+func parseLocales(getenv func(key string) string, simplify func(lang string) string) []string {
+	var locale string
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale = getenv(key); locale != "" {
+			break
+		}
 	}
-	if v := os.Getenv("LANG"); v != "" {
-		return simplifiedLanguage(v)
+	if locale == "C" || locale == "POSIX" || strings.HasPrefix(locale, "C.") {
+		return []string{"default"}
+	}
+
+	var langs []string
+	for _, s := range strings.Split(getenv("LANGUAGE"), ":") {
+		if s = simplify(s); s != "" {
+			langs = append(langs, s)
+		}
 	}
-	return "default"
+	if len(langs) != 0 && locale != "" {
+		return langs
+	}
+	if s := simplify(locale); s != "" {
+		return []string{s}
+	}
+	return []string{"default"}
 }
 
 func simplifiedLanguage(lang string) string {
@@ -32,3 +69,106 @@ func simplifiedLanguage(lang string) string {
 	}
 	return strings.TrimSpace(lang)
 }
+
+// fallbackLanguages returns the languages of the lang's fallback chain:
+// lang and its parents, the fallbacks and their parents, then "default".
+// The codeset and modifier variants are in the order of GNU's gettext,
+// see explodeLanguage. The invalid names (see isLanguageName) are
+// skipped, they aren't looked up in the FileSystem.
+//
+//	fallbackLanguages("de_AT", []string{"en"}) => ["de_AT", "de", "en", "default"]
+func fallbackLanguages(lang string, fallbacks []string) []string {
+	var (
+		langs []string
+		seen  = make(map[string]bool)
+	)
+	for _, s := range append([]string{lang}, append(fallbacks, "default")...) {
+		if !isLanguageName(s) {
+			continue
+		}
+		for _, s := range explodeLanguage(s) {
+			if !seen[s] {
+				seen[s] = true
+				langs = append(langs, s)
+			}
+		}
+	}
+	return langs
+}
+
+// isLanguageName reports whether lang can be the name of a locale dir:
+// the languages may come from the users (such as the Accept-Language
+// of a HTTP request), so a name with "/", `\` or ".." is rejected.
+func isLanguageName(lang string) bool {
+	return lang != "" && !strings.ContainsAny(lang, "/\\\x00") && !strings.Contains(lang, "..")
+}
+
+// explodeLanguage returns the locale name and its parents, like GNU's
+// gettext looks up language[_territory][.codeset][@modifier]: the
+// modifier is preferred to the territory, the territory to the codeset,
+// and the codeset to its normalized name.
+//
+//	explodeLanguage("sr_RS@latin") => ["sr_RS@latin", "sr@latin", "sr_RS", "sr"]
+//	explodeLanguage("de_DE.UTF-8") => ["de_DE.UTF-8", "de_DE.utf8", "de_DE", "de.UTF-8", "de.utf8", "de"]
+func explodeLanguage(lang string) []string {
+	var (
+		codesets  = []string{""}
+		modifiers = []string{""}
+		bases     []string
+	)
+	lang = strings.TrimSpace(lang)
+	if idx := strings.Index(lang, "@"); idx != -1 {
+		modifiers = []string{lang[idx:], ""}
+		lang = lang[:idx]
+	}
+	if idx := strings.Index(lang, "."); idx != -1 {
+		codesets = []string{lang[idx:]}
+		if s := "." + normalizeCodeset(lang[idx+1:]); s != lang[idx:] && s != "." {
+			codesets = append(codesets, s)
+		}
+		codesets = append(codesets, "")
+		lang = lang[:idx]
+	}
+	for s := lang; s != ""; {
+		bases = append(bases, s)
+		idx := strings.LastIndexAny(s, "_-")
+		if idx < 0 {
+			break
+		}
+		s = s[:idx]
+	}
+
+	var langs []string
+	for _, modifier := range modifiers {
+		for _, base := range bases {
+			for _, codeset := range codesets {
+				langs = append(langs, base+codeset+modifier)
+			}
+		}
+	}
+	return langs
+}
+
+// normalizeCodeset returns the codeset name normalized like GNU's
+// gettext: the lower case letters and digits, "iso" is prepended if
+// it's only digits. "UTF-8" => "utf8", "8859-1" => "iso88591".
+func normalizeCodeset(codeset string) string {
+	var (
+		buf    []byte
+		digits = true
+	)
+	for i := 0; i < len(codeset); i++ {
+		switch c := codeset[i]; {
+		case c >= 'a' && c <= 'z':
+			buf, digits = append(buf, c), false
+		case c >= 'A' && c <= 'Z':
+			buf, digits = append(buf, c+'a'-'A'), false
+		case c >= '0' && c <= '9':
+			buf = append(buf, c)
+		}
+	}
+	if digits && len(buf) != 0 {
+		return "iso" + string(buf)
+	}
+	return string(buf)
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLanguages(t *testing.T) {
+	for _, v := range []struct {
+		env    map[string]string
+		expect string
+	}{
+		{map[string]string{}, "default"},
+		{map[string]string{"LANG": "zh_CN.UTF-8"}, "zh_CN"},
+		{map[string]string{"LANG": "zh_CN", "LC_MESSAGES": "de_DE.UTF-8"}, "de_DE"},
+		{map[string]string{"LANG": "zh_CN", "LC_MESSAGES": "de_DE", "LC_ALL": "fr_FR"}, "fr_FR"},
+		{map[string]string{"LANG": "de_AT.UTF-8", "LANGUAGE": "de_AT:de:en"}, "de_AT de en"},
+		{map[string]string{"LANG": "de_AT", "LANGUAGE": ":sr@latin::en:"}, "sr en"},
+		{map[string]string{"LANGUAGE": "de:en"}, "default"},
+		{map[string]string{"LANG": "C", "LANGUAGE": "de:en"}, "default"},
+		{map[string]string{"LC_ALL": "POSIX", "LANG": "de_DE", "LANGUAGE": "de:en"}, "default"},
+		{map[string]string{"LC_ALL": "C.UTF-8", "LANGUAGE": "de"}, "default"},
+	} {
+		got := strings.Join(parseLanguages(func(key string) string { return v.env[key] }), " ")
+		tAssert(t, got == v.expect, v.env, got, v.expect)
+	}
+}
+
+func TestExplodeLanguage(t *testing.T) {
+	for _, v := range []struct {
+		lang   string
+		expect string
+	}{
+		{"de", "de"},
+		{"de_DE.UTF-8", "de_DE.UTF-8 de_DE.utf8 de_DE de.UTF-8 de.utf8 de"},
+		{"de_DE.UTF-8@euro", "de_DE.UTF-8@euro de_DE.utf8@euro de_DE@euro de.UTF-8@euro de.utf8@euro de@euro " +
+			"de_DE.UTF-8 de_DE.utf8 de_DE de.UTF-8 de.utf8 de"},
+		{"sr@latin", "sr@latin sr"},
+		{"el_GR.8859-7", "el_GR.8859-7 el_GR.iso88597 el_GR el.8859-7 el.iso88597 el"},
+		{"", ""},
+	} {
+		got := strings.Join(explodeLanguage(v.lang), " ")
+		tAssert(t, got == v.expect, got, v.expect)
+	}
+}
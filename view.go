@@ -0,0 +1,438 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/chai2010/gettext-go/plural"
+	"github.com/chai2010/gettext-go/po"
+)
+
+// _View is an immutable Gettexter of a language and a domain,
+// see Gettexter.WithLanguage.
+//
+// It holds the translators loaded by its _Locale, the lookups don't
+// take any lock. SetDomain, SetLanguage, SetFallbacks and SetMissingHandler
+// return a new view, the view itself is never changed. SetCacheSize,
+// SetUseFuzzy and Invalidate change the catalogs of its _Locale.
+// It's also the snapshot of a _Locale.
+type _View struct {
+	locale    *_Locale
+	lang      string
+	domain    string
+	fallbacks []string
+	langs     []string // lang and the fallbacks, see fallbackLanguages
+	tr        trChain
+	domains   sync.Map        // the other domains of the D* functions, domain => trChain
+	missing   *missingHandler // see SetMissingHandler, nil uses the _Locale's
+}
+
+var _ Gettexter = (*_View)(nil)
+
+// newView returns the view of the domain and lang, p.mutex must be held.
+func (p *_Locale) newView(domain, lang string, fallbacks []string) *_View {
+	v := &_View{
+		locale:    p,
+		lang:      lang,
+		domain:    domain,
+		fallbacks: fallbacks,
+		langs:     fallbackLanguages(lang, fallbacks),
+	}
+	v.tr = p.loadTrChain(domain, v.langs)
+	return v
+}
+
+func (p *_Locale) WithLanguage(lang string) Gettexter {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	cur := p.view()
+	return p.cachedView(cur.domain, lang, cur.fallbacks)
+}
+
+func (p *_Locale) WithDomain(domain string) Gettexter {
+	cur := p.view()
+	if domain == "" {
+		domain = cur.domain
+	}
+	return p.cachedView(domain, cur.lang, cur.fallbacks)
+}
+
+// maxViews is the max number of the views cached by a _Locale.
+const maxViews = 1024
+
+// cachedView returns the view of the domain, lang and fallbacks.
+//
+// The views are immutable, so they are cached: the views of a cached
+// key (such as a view per HTTP request) don't take the mutex. The least
+// recently used views are evicted if the cache is full, the views of an
+// evicted catalog are removed (see removeViews), and the cache is dropped
+// if the catalogs are changed (see resetViews).
+func (p *_Locale) cachedView(domain, lang string, fallbacks []string) *_View {
+	key := domain + "\x00" + lang + "\x00" + strings.Join(fallbacks, "\x00")
+	if v, ok := p.views.Get(key); ok {
+		return v
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if v, ok := p.views.Get(key); ok {
+		return v
+	}
+	v := p.newView(domain, lang, fallbacks)
+	p.views.Add(key, v, maxViews)
+	return v
+}
+
+// resetViews drops the cached views, p.mutex must be held.
+func (p *_Locale) resetViews() {
+	p.views.Reset()
+}
+
+// removeViews removes the cached views which hold the removed catalog
+// of the key, p.mutex must be held.
+func (p *_Locale) removeViews(key trCacheKey, tr trChain) {
+	if len(tr) == 0 {
+		return
+	}
+	p.views.RemoveIf(func(v *_View) bool {
+		if v.domain != key.domain {
+			return false
+		}
+		for _, lang := range v.langs {
+			if lang == key.lang {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// viewCache is the LRU cache of the views of a _Locale, by domain,
+// lang and fallbacks. Get doesn't lock, the writers hold the _Locale's
+// mutex.
+//
+// The recency is the clock of the last Add before the last use, so the
+// lookups don't write a shared counter.
+type viewCache struct {
+	m     sync.Map // key => *viewCacheEntry
+	n     int
+	clock int64 // atomic, incremented by Add
+}
+
+type viewCacheEntry struct {
+	v    *_View
+	used int64 // atomic, the clock of the last use
+}
+
+// Get returns the view of the key, and marks it as used.
+func (c *viewCache) Get(key string) (*_View, bool) {
+	x, ok := c.m.Load(key)
+	if !ok {
+		return nil, false
+	}
+	e := x.(*viewCacheEntry)
+	if now := atomic.LoadInt64(&c.clock); atomic.LoadInt64(&e.used) != now {
+		atomic.StoreInt64(&e.used, now)
+	}
+	return e.v, true
+}
+
+// Add adds the view of the key, the least recently used eighth of
+// the views is evicted if there are max ones.
+func (c *viewCache) Add(key string, v *_View, max int) {
+	if c.n >= max {
+		var entries []*viewCacheEntry
+		keys := make(map[*viewCacheEntry]interface{}, c.n)
+		c.m.Range(func(k, x interface{}) bool {
+			entries = append(entries, x.(*viewCacheEntry))
+			keys[x.(*viewCacheEntry)] = k
+			return true
+		})
+		sort.Slice(entries, func(i, j int) bool {
+			return atomic.LoadInt64(&entries[i].used) < atomic.LoadInt64(&entries[j].used)
+		})
+		for _, e := range entries[:len(entries)/8+1] {
+			c.m.Delete(keys[e])
+			c.n--
+		}
+	}
+	now := atomic.AddInt64(&c.clock, 1)
+	c.m.Store(key, &viewCacheEntry{v: v, used: now})
+	c.n++
+}
+
+// RemoveIf removes the views of the cond.
+func (c *viewCache) RemoveIf(cond func(v *_View) bool) {
+	c.m.Range(func(k, x interface{}) bool {
+		if cond(x.(*viewCacheEntry).v) {
+			c.m.Delete(k)
+			c.n--
+		}
+		return true
+	})
+}
+
+// Reset removes all the views.
+func (c *viewCache) Reset() {
+	c.m.Range(func(k, _ interface{}) bool {
+		c.m.Delete(k)
+		return true
+	})
+	c.n = 0
+}
+
+// Len returns the number of the views.
+func (c *viewCache) Len() int {
+	return c.n
+}
+
+// with returns the view of the domain, lang and fallbacks,
+// with the missing handler of p.
+func (p *_View) with(domain, lang string, fallbacks []string) *_View {
+	v := p.locale.cachedView(domain, lang, fallbacks)
+	if p.missing != nil {
+		v = v.withMissing(p.missing)
+	}
+	return v
+}
+
+// withMissing returns a copy of the view with the missing handler.
+func (p *_View) withMissing(h *missingHandler) *_View {
+	return &_View{
+		locale:    p.locale,
+		lang:      p.lang,
+		domain:    p.domain,
+		fallbacks: p.fallbacks,
+		langs:     p.langs,
+		tr:        p.tr,
+		missing:   h,
+	}
+}
+
+func (p *_View) FileSystem() FileSystem {
+	return p.locale.fs
+}
+
+func (p *_View) GetDomain() string {
+	return p.domain
+}
+
+func (p *_View) SetDomain(domain string) Gettexter {
+	return p.WithDomain(domain)
+}
+
+func (p *_View) WithDomain(domain string) Gettexter {
+	if domain == "" || domain == p.domain {
+		return p
+	}
+	return p.with(domain, p.lang, p.fallbacks)
+}
+
+func (p *_View) GetLanguage() string {
+	return p.lang
+}
+
+func (p *_View) SetLanguage(lang string) Gettexter {
+	return p.WithLanguage(lang)
+}
+
+func (p *_View) WithLanguage(lang string) Gettexter {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if lang == p.lang {
+		return p
+	}
+	return p.with(p.domain, lang, p.fallbacks)
+}
+
+func (p *_View) GetFallbacks() []string {
+	return append([]string(nil), p.fallbacks...)
+}
+
+func (p *_View) SetFallbacks(langs ...string) Gettexter {
+	return p.with(p.domain, p.lang, append([]string(nil), langs...))
+}
+
+func (p *_View) SetCacheSize(n int) Gettexter {
+	p.locale.SetCacheSize(n)
+	return p
+}
+
+func (p *_View) SetUseFuzzy(useFuzzy bool) Gettexter {
+	p.locale.SetUseFuzzy(useFuzzy)
+	return p
+}
+
+func (p *_View) Invalidate(domain, lang string) {
+	p.locale.Invalidate(domain, lang)
+}
+
+func (p *_View) LoadError(domain, lang string) error {
+	if domain == "" {
+		domain = p.domain
+	}
+	if lang == "" {
+		lang = p.lang
+	}
+	return p.locale.LoadError(domain, lang)
+}
+
+func (p *_View) Diagnostics(domain, lang string) []po.Diagnostic {
+	if domain == "" {
+		domain = p.domain
+	}
+	if lang == "" {
+		lang = p.lang
+	}
+	return p.locale.Diagnostics(domain, lang)
+}
+
+// SetMissingHandler returns a new view with the missing handler,
+// the _Locale's handler isn't changed.
+func (p *_View) SetMissingHandler(fn func(m Missing)) Gettexter {
+	return p.withMissing(&missingHandler{fn: fn})
+}
+
+func (p *_View) Gettext(msgid string) string {
+	s, ok := p.tr.PGettext("", msgid)
+	return p.checkMissing(s, ok, p.domain, "", msgid, "")
+}
+
+func (p *_View) PGettext(msgctxt, msgid string) string {
+	s, ok := p.tr.PGettext(msgctxt, msgid)
+	return p.checkMissing(s, ok, p.domain, msgctxt, msgid, "")
+}
+
+func (p *_View) NGettext(msgid, msgidPlural string, n int) string {
+	s, ok := p.tr.PNGettext("", msgid, msgidPlural, n)
+	return p.checkMissing(s, ok, p.domain, "", msgid, msgidPlural)
+}
+
+func (p *_View) PNGettext(msgctxt, msgid, msgidPlural string, n int) string {
+	s, ok := p.tr.PNGettext(msgctxt, msgid, msgidPlural, n)
+	return p.checkMissing(s, ok, p.domain, msgctxt, msgid, msgidPlural)
+}
+
+func (p *_View) NGettext64(msgid, msgidPlural string, n int64) string {
+	s, ok := p.tr.PNGettext("", msgid, msgidPlural, plural.Int64(n))
+	return p.checkMissing(s, ok, p.domain, "", msgid, msgidPlural)
+}
+
+func (p *_View) PNGettext64(msgctxt, msgid, msgidPlural string, n int64) string {
+	s, ok := p.tr.PNGettext(msgctxt, msgid, msgidPlural, plural.Int64(n))
+	return p.checkMissing(s, ok, p.domain, msgctxt, msgid, msgidPlural)
+}
+
+func (p *_View) NGettextDecimal(msgid, msgidPlural string, n plural.Operands) string {
+	s, ok := p.tr.PNGettextDecimal("", msgid, msgidPlural, n)
+	return p.checkMissing(s, ok, p.domain, "", msgid, msgidPlural)
+}
+
+func (p *_View) PNGettextDecimal(msgctxt, msgid, msgidPlural string, n plural.Operands) string {
+	s, ok := p.tr.PNGettextDecimal(msgctxt, msgid, msgidPlural, n)
+	return p.checkMissing(s, ok, p.domain, msgctxt, msgid, msgidPlural)
+}
+
+func (p *_View) OGettext(msgid string, n int) string {
+	s, ok := p.tr.POGettext("", msgid, n)
+	return p.checkMissingOrdinal(s, ok, "", msgid)
+}
+
+func (p *_View) POGettext(msgctxt, msgid string, n int) string {
+	s, ok := p.tr.POGettext(msgctxt, msgid, n)
+	return p.checkMissingOrdinal(s, ok, msgctxt, msgid)
+}
+
+func (p *_View) DGettext(domain, msgid string) string {
+	return p.gettext(domain, "", msgid, "", 0)
+}
+
+func (p *_View) DNGettext(domain, msgid, msgidPlural string, n int) string {
+	return p.gettext(domain, "", msgid, msgidPlural, n)
+}
+
+func (p *_View) DPGettext(domain, msgctxt, msgid string) string {
+	return p.gettext(domain, msgctxt, msgid, "", 0)
+}
+
+func (p *_View) DPNGettext(domain, msgctxt, msgid, msgidPlural string, n int) string {
+	return p.gettext(domain, msgctxt, msgid, msgidPlural, n)
+}
+
+func (p *_View) Getdata(name string) []byte {
+	return p.getdata(p.domain, name)
+}
+
+func (p *_View) DGetdata(domain, name string) []byte {
+	return p.getdata(domain, name)
+}
+
+func (p *_View) gettext(domain, msgctxt, msgid, msgidPlural string, n int) string {
+	chain := p.tr
+	if domain != p.domain {
+		chain = p.domainTrChain(domain)
+	}
+	s, ok := chain.PNGettext(msgctxt, msgid, msgidPlural, n)
+	return p.checkMissing(s, ok, domain, msgctxt, msgid, msgidPlural)
+}
+
+// checkMissing reports the message to the _Locale's missing handler
+// if it's not translated, and returns s.
+func (p *_View) checkMissing(s string, ok bool, domain, msgctxt, msgid, msgidPlural string) string {
+	if !ok {
+		p.reportMissing(Missing{
+			Domain:      domain,
+			Language:    p.lang,
+			MsgContext:  msgctxt,
+			MsgId:       msgid,
+			MsgIdPlural: msgidPlural,
+		})
+	}
+	return s
+}
+
+// checkMissingOrdinal is like checkMissing, but for an ordinal message.
+func (p *_View) checkMissingOrdinal(s string, ok bool, msgctxt, msgid string) string {
+	if !ok {
+		p.reportMissing(Missing{
+			Domain:     p.domain,
+			Language:   p.lang,
+			MsgContext: msgctxt,
+			MsgId:      msgid,
+			Ordinal:    true,
+		})
+	}
+	return s
+}
+
+// domainTrChain returns the translators of another domain,
+// the _Locale loads them on the first use.
+func (p *_View) domainTrChain(domain string) trChain {
+	if v, ok := p.domains.Load(domain); ok {
+		return v.(trChain)
+	}
+	p.locale.mutex.Lock()
+	chain := p.locale.loadTrChain(domain, p.langs)
+	p.locale.mutex.Unlock()
+
+	v, _ := p.domains.LoadOrStore(domain, chain)
+	return v.(trChain)
+}
+
+func (p *_View) getdata(domain, name string) []byte {
+	for _, lang := range p.langs {
+		if data, err := p.locale.fs.LoadResourceFile(domain, lang, name); err == nil {
+			return data
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestView(t *testing.T) {
+	l := New("hello", "./examples/locale").SetLanguage("zh_TW")
+
+	zh := l.WithLanguage("zh_CN")
+	testLocal_zh_CN(t, zh)
+	tAssert(t, l.GetLanguage() == "zh_TW", l.GetLanguage())
+	tAssert(t, zh.GetDomain() == "hello", zh.GetDomain())
+
+	// the Set methods return a new view
+	tw := zh.SetLanguage("zh_TW")
+	tAssert(t, tw.GetLanguage() == "zh_TW", tw.GetLanguage())
+	tAssert(t, zh.GetLanguage() == "zh_CN", zh.GetLanguage())
+	tAssert(t, tw.Gettext("Hello, world!") == l.Gettext("Hello, world!"))
+	testLocal_zh_CN(t, zh)
+
+	other := zh.WithDomain("other")
+	tAssert(t, other.GetDomain() == "other", other.GetDomain())
+	tAssert(t, other.Gettext("Hello, world!") == "Hello, world!")
+	tAssert(t, zh.WithLanguage("zh_CN") == zh)
+}
+
+func TestView_missingHandler(t *testing.T) {
+	var parent, tenant []Missing
+	l := New("hello", "", mapFS{}).SetLanguage("de").SetMissingHandler(func(m Missing) {
+		parent = append(parent, m)
+	})
+
+	v := l.WithLanguage("fr").SetMissingHandler(func(m Missing) {
+		tenant = append(tenant, m)
+	})
+	v.Gettext("Open")
+	v.WithDomain("lib").Gettext("Close") // the new views keep the handler
+	tAssert(t, len(tenant) == 2 && len(parent) == 0, tenant, parent)
+
+	// a disabled view doesn't change the parent
+	l.WithLanguage("fr").SetMissingHandler(nil).Gettext("Save")
+	l.Gettext("Save")
+	l.WithLanguage("fr").Gettext("Save")
+	tAssert(t, len(parent) == 2, parent)
+	tAssert(t, len(tenant) == 2, tenant)
+}
+
+func TestView_concurrent(t *testing.T) {
+	l := New("hello", "./examples/locale")
+	zh, tw := l.WithLanguage("zh_CN"), l.WithLanguage("zh_TW")
+	want := tw.Gettext("Hello, world!")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if i%2 == 0 {
+					l.SetLanguage("zh_CN")
+				} else {
+					l.SetLanguage("en_US")
+				}
+				if got := zh.Gettext("Hello, world!"); got != "你好, 世界!" {
+					t.Errorf("zh_CN: got = %q", got)
+				}
+				if got := tw.Gettext("Hello, world!"); got != want {
+					t.Errorf("zh_TW: got = %q", got)
+				}
+				l.WithLanguage("zh_TW").Gettext("Hello, world!")
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestView_cached(t *testing.T) {
+	l := New("hello", "./examples/locale")
+	zh := l.WithLanguage("zh_CN")
+	tAssert(t, l.WithLanguage("zh_CN") == zh)
+	tAssert(t, l.WithLanguage("zh_TW").WithLanguage("zh_CN") == zh)
+	tAssert(t, l.WithDomain("other").WithDomain("hello").WithLanguage("zh_CN") == zh)
+	tAssert(t, zh.SetFallbacks("en_US") != zh)
+
+	// the catalogs are changed
+	l.Invalidate("", "")
+	tAssert(t, l.WithLanguage("zh_CN") != zh)
+	testLocal_zh_CN(t, l.WithLanguage("zh_CN"))
+
+	// full, the recently used views are kept
+	zh = l.WithLanguage("zh_CN")
+	for i := 0; i < maxViews+10; i++ {
+		l.WithLanguage(fmt.Sprintf("xx_%d", i))
+		tAssert(t, l.WithLanguage("zh_CN") == zh, i)
+	}
+	tAssert(t, l.(*_Locale).views.Len() <= maxViews, l.(*_Locale).views.Len())
+}
+
+// BenchmarkView_WithLanguage creates a view per iteration, like a view
+// per HTTP request; run it with -cpu=1,8,64.
+func BenchmarkView_WithLanguage(b *testing.B) {
+	l := New("hello", "./examples/locale")
+	langs := []string{"zh_CN", "zh_TW", "en_US"}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			l.WithLanguage(langs[i%len(langs)]).Gettext("Hello, world!")
+		}
+	})
+}
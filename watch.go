@@ -0,0 +1,199 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watch polls the messages files (root/<lang>/LC_MESSAGES/*.po|mo|json)
+// of the Gettexter's OS file system every interval (one second if it's
+// not positive), and reloads the catalogs of the changed files. The
+// reloaded catalogs are swapped atomically, see Gettexter.Invalidate.
+// The OS layers of a LayeredFS are watched too.
+//
+// A changed file which can't be loaded is reported to onError (if not
+// nil), and the old catalog is kept. It returns an error if the file
+// system has no OS directory, the stop func stops the watcher.
+//
+// Examples:
+//
+//	g := New("hello", "locale")
+//	stop, err := Watch(g, time.Second, func(err error) { log.Println(err) })
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer stop()
+func Watch(g Gettexter, interval time.Duration, onError func(err error)) (stop func(), err error) {
+	var dirs []*osFS
+	for _, fs := range fsLayers(g.FileSystem()) {
+		if fs, ok := fs.(*osFS); ok {
+			dirs = append(dirs, fs)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("gettext: Watch: %v is not an OS directory", g.FileSystem())
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	w := &watcher{
+		g:       g,
+		dirs:    dirs,
+		onError: onError,
+		done:    make(chan struct{}),
+	}
+	w.files = w.scan()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.run(interval)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(w.done)
+			wg.Wait()
+		})
+	}, nil
+}
+
+type watcher struct {
+	g       Gettexter
+	dirs    []*osFS
+	onError func(err error)
+	done    chan struct{}
+	files   map[string]fileStamp // path => stamp
+}
+
+// fileStamp is the state of a file, a change of any field
+// is a change of the file.
+type fileStamp struct {
+	domain  string
+	lang    string
+	modTime time.Time
+	size    int64
+}
+
+func (w *watcher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll reloads the catalogs of the files changed since the last scan.
+func (w *watcher) poll() {
+	files := w.scan()
+
+	var (
+		changed = make(map[trCacheKey]bool)
+		broken  = make(map[trCacheKey]bool)
+	)
+	for path, s := range files {
+		if old, ok := w.files[path]; ok && old == s {
+			continue
+		}
+		key := trCacheKey{s.domain, s.lang}
+		changed[key] = true
+		if err := w.check(path, s); err != nil {
+			broken[key] = true
+			w.reportError(err)
+		}
+	}
+	for path, s := range w.files {
+		if _, ok := files[path]; !ok {
+			changed[trCacheKey{s.domain, s.lang}] = true
+		}
+	}
+	w.files = files
+
+	for key := range changed {
+		if !broken[key] {
+			w.g.Invalidate(key.domain, key.lang)
+		}
+	}
+}
+
+// scan returns the messages files of the dirs.
+func (w *watcher) scan() map[string]fileStamp {
+	files := make(map[string]fileStamp)
+	for _, fs := range w.dirs {
+		w.scanDir(fs.root, fs.category, files)
+	}
+	return files
+}
+
+func (w *watcher) scanDir(root, category string, files map[string]fileStamp) {
+	langs, err := ioutil.ReadDir(root)
+	if err != nil {
+		w.reportError(fmt.Errorf("gettext: Watch: %v", err))
+		return
+	}
+	for _, dir := range langs {
+		if !dir.IsDir() {
+			continue
+		}
+		list, err := ioutil.ReadDir(filepath.Join(root, dir.Name(), category))
+		if err != nil {
+			continue
+		}
+		for _, fi := range list {
+			ext := strings.ToLower(filepath.Ext(fi.Name()))
+			if fi.IsDir() || (ext != ".po" && ext != ".mo" && ext != ".json") {
+				continue
+			}
+			path := filepath.Join(root, dir.Name(), category, fi.Name())
+			files[path] = fileStamp{
+				domain:  strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name())),
+				lang:    dir.Name(),
+				modTime: fi.ModTime(),
+				size:    fi.Size(),
+			}
+		}
+	}
+}
+
+// check loads the changed file, as the _Locale does.
+func (w *watcher) check(path string, s fileStamp) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("gettext: reload %s: %v", path, err)
+	}
+	name := fmt.Sprintf("%s_%s%s", s.domain, s.lang, filepath.Ext(path))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".po":
+		_, err = newPoTranslator(name, data, false)
+	case ".mo":
+		_, err = newMoTranslator(name, data)
+	case ".json":
+		_, err = newJsonTranslator(s.lang, name, data)
+	}
+	if err != nil {
+		return fmt.Errorf("gettext: reload %s: %v", path, err)
+	}
+	return nil
+}
+
+func (w *watcher) reportError(err error) {
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
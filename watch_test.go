@@ -0,0 +1,99 @@
+// Copyright 2020 ChaiShushan <chaishushan{AT}gmail.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gettext
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	root, err := ioutil.TempDir("", "gettext-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	dir := filepath.Join(root, "zh_CN", "LC_MESSAGES")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(name, data string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	writeFile("hello.po", "msgid \"Hello\"\nmsgstr \"Hello(v1)\"\n", now)
+
+	g := New("hello", root).SetLanguage("zh_CN")
+	tAssert(t, g.Gettext("Hello") == "Hello(v1)", g.Gettext("Hello"))
+
+	var errs []error
+	w := &watcher{g: g, dirs: []*osFS{g.FileSystem().(*osFS)}, onError: func(err error) { errs = append(errs, err) }}
+	w.files = w.scan()
+
+	// modified
+	writeFile("hello.po", "msgid \"Hello\"\nmsgstr \"Hello(v2)\"\n", now.Add(time.Second))
+	w.poll()
+	tAssert(t, g.Gettext("Hello") == "Hello(v2)", g.Gettext("Hello"))
+
+	// a broken file keeps the old catalog
+	writeFile("hello.mo", "not a mo file", now.Add(2*time.Second))
+	w.poll()
+	tAssert(t, len(errs) == 1, errs)
+	tAssert(t, g.Gettext("Hello") == "Hello(v2)", g.Gettext("Hello"))
+
+	// removed
+	if err := os.Remove(filepath.Join(dir, "hello.po")); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(dir, "hello.mo"))
+	w.poll()
+	tAssert(t, g.Gettext("Hello") == "Hello", g.Gettext("Hello"))
+	tAssert(t, len(errs) == 1, errs)
+}
+
+func TestWatch_run(t *testing.T) {
+	root, err := ioutil.TempDir("", "gettext-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	dir := filepath.Join(root, "zh_CN", "LC_MESSAGES")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "hello.po")
+
+	g := New("hello", root).SetLanguage("zh_CN")
+	stop, err := Watch(g, 10*time.Millisecond, func(err error) { t.Error(err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	if err := ioutil.WriteFile(path, []byte("msgid \"Hello\"\nmsgstr \"Hello(v1)\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 500 && g.Gettext("Hello") != "Hello(v1)"; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	tAssert(t, g.Gettext("Hello") == "Hello(v1)", g.Gettext("Hello"))
+
+	stop()
+	stop()
+
+	_, err = Watch(NewWithCatalogs("hello"), time.Second, nil)
+	tAssert(t, err != nil)
+}